@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ action.Action                   = (*endChangeAction)(nil)
+	_ action.ActionWithConfigure      = (*endChangeAction)(nil)
+	_ action.ActionWithValidateConfig = (*endChangeAction)(nil)
+)
+
+type endChangeAction struct {
+	changes sdpconnect.ChangesServiceClient
+}
+
+func NewEndChangeAction() action.Action {
+	return &endChangeAction{}
+}
+
+func (a *endChangeAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_end_change"
+}
+
+func (a *endChangeAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "Ends a change started by overmind_start_change, taking the after snapshot of its blast " +
+			"radius so Overmind can show what actually changed. Trigger it from the lifecycle action_trigger " +
+			"of the resources being changed, on after_create or after_update. Waits up to timeouts.invoke, " +
+			"10 minutes by default, for the snapshot to be saved.",
+		Attributes: changeSnapshotActionAttributes("end"),
+		Blocks: map[string]actionschema.Block{
+			"timeouts": invokeTimeoutsBlock(),
+		},
+	}
+}
+
+func (a *endChangeAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config changeSnapshotActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(config.validate()...)
+}
+
+func (a *endChangeAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	a.changes = clients.Changes
+}
+
+func (a *endChangeAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "EndChange Invoke")
+	defer span.End()
+
+	var config changeSnapshotActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.id", config.ChangeID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(config.ChangeID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid change ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	timeout, diags := config.Timeouts.InvokeTimeout(defaultChangeSnapshotTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	streamCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Ending change %s", config.ChangeID.ValueString()),
+	})
+
+	err = a.endChange(streamCtx, uuidBytes, func(msg *sdp.EndChangeResponse) {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Ending change %s: %s (%d items, %d edges)",
+				config.ChangeID.ValueString(), msg.GetState().ToMessage(), msg.GetNumItems(), msg.GetNumEdges()),
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.Append(changeSnapshotError(streamCtx, err, "end", config.ChangeID.ValueString(), timeout)...)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "EndChange failed")
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Ended change %s", config.ChangeID.ValueString()),
+	})
+}
+
+// endChange calls EndChange and reports each state the stream sends,
+// returning once the snapshot is saved.
+func (a *endChangeAction) endChange(ctx context.Context, changeUUID []byte, report func(*sdp.EndChangeResponse)) error {
+	stream, err := a.changes.EndChange(ctx, connect.NewRequest(&sdp.EndChangeRequest{
+		ChangeUUID: changeUUID,
+	}))
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	state := sdp.EndChangeResponse_STATE_UNSPECIFIED
+	for stream.Receive() {
+		state = stream.Msg().GetState()
+		report(stream.Msg())
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	if state != sdp.EndChangeResponse_STATE_DONE {
+		return fmt.Errorf("stream ended while the snapshot was in state %s", state)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultReapplyWindow is how far back label rules are reapplied when no
+// window is configured.
+const defaultReapplyWindow = 30 * 24 * time.Hour
+
+var (
+	_ action.Action                   = (*reapplyLabelRuleAction)(nil)
+	_ action.ActionWithConfigure      = (*reapplyLabelRuleAction)(nil)
+	_ action.ActionWithValidateConfig = (*reapplyLabelRuleAction)(nil)
+)
+
+type reapplyLabelRuleAction struct {
+	labels sdpconnect.LabelServiceClient
+}
+
+type reapplyLabelRuleActionModel struct {
+	LabelRuleID types.String `tfsdk:"label_rule_id"`
+	Window      types.String `tfsdk:"window"`
+	EndAt       types.String `tfsdk:"end_at"`
+}
+
+func NewReapplyLabelRuleAction() action.Action {
+	return &reapplyLabelRuleAction{}
+}
+
+func (a *reapplyLabelRuleAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_reapply_label_rule"
+}
+
+func (a *reapplyLabelRuleAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "Reapplies a label rule to the changes in a time window: the rule's label is removed from " +
+			"those changes and then applied again wherever the current instructions match. Trigger it from the " +
+			"overmind_label_rule's lifecycle action_trigger, on after_update, to backfill labels whenever the " +
+			"rule is edited.",
+		Attributes: map[string]actionschema.Attribute{
+			"label_rule_id": actionschema.StringAttribute{
+				Description: "UUID of the label rule to reapply, usually overmind_label_rule.<name>.id.",
+				Required:    true,
+			},
+			"window": actionschema.StringAttribute{
+				Description: "How far back from end_at to reapply the rule, as a Go duration string such as \"168h\". " +
+					"Defaults to 30 days.",
+				Optional: true,
+			},
+			"end_at": actionschema.StringAttribute{
+				Description: "RFC 3339 timestamp of the end of the window. Defaults to the time the action runs.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (a *reapplyLabelRuleAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config reapplyLabelRuleActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.LabelRuleID.IsNull() && !config.LabelRuleID.IsUnknown() {
+		if _, err := uuidToBytes(config.LabelRuleID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("label_rule_id"), "Invalid label rule ID", err.Error())
+		}
+	}
+
+	if !config.Window.IsNull() && !config.Window.IsUnknown() {
+		window, err := time.ParseDuration(config.Window.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("window"), "Invalid reapply window",
+				fmt.Sprintf("window must be a Go duration string such as \"168h\": %s", err))
+		} else if window <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("window"), "Invalid reapply window",
+				fmt.Sprintf("window must be positive, got %q.", config.Window.ValueString()))
+		}
+	}
+
+	if !config.EndAt.IsNull() && !config.EndAt.IsUnknown() {
+		if _, err := time.Parse(time.RFC3339, config.EndAt.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("end_at"), "Invalid end_at timestamp",
+				fmt.Sprintf("end_at must be an RFC 3339 timestamp: %s", err))
+		}
+	}
+}
+
+func (a *reapplyLabelRuleAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	a.labels = clients.Labels
+}
+
+func (a *reapplyLabelRuleAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "ReapplyLabelRule Invoke")
+	defer span.End()
+
+	var config reapplyLabelRuleActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.labelRule.id", config.LabelRuleID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(config.LabelRuleID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid label rule ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	// Both were checked by ValidateConfig.
+	window := defaultReapplyWindow
+	if !config.Window.IsNull() {
+		window, _ = time.ParseDuration(config.Window.ValueString())
+	}
+	endAt := time.Now()
+	if !config.EndAt.IsNull() {
+		endAt, _ = time.Parse(time.RFC3339, config.EndAt.ValueString())
+	}
+	startAt := endAt.Add(-window)
+
+	span.SetAttributes(
+		attribute.String("ovm.labelRule.reapplyStart", startAt.Format(time.RFC3339)),
+		attribute.String("ovm.labelRule.reapplyEnd", endAt.Format(time.RFC3339)),
+	)
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Reapplying label rule %s to changes between %s and %s",
+			config.LabelRuleID.ValueString(), startAt.Format(time.RFC3339), endAt.Format(time.RFC3339)),
+	})
+
+	reapplyResp, err := a.labels.ReapplyLabelRuleInTimeRange(ctx, connect.NewRequest(&sdp.ReapplyLabelRuleInTimeRangeRequest{
+		UUID:    uuidBytes,
+		StartAt: timestamppb.New(startAt),
+		EndAt:   timestamppb.New(endAt),
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			resp.Diagnostics.AddAttributeError(path.Root("label_rule_id"), "Label rule not found",
+				fmt.Sprintf("No label rule with ID %s exists.", config.LabelRuleID.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Failed to reapply label rule", err.Error())
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ReapplyLabelRuleInTimeRange failed")
+		return
+	}
+
+	relabelled := len(reapplyResp.Msg.GetChangeUUID())
+	span.SetAttributes(attribute.Int("ovm.labelRule.relabelledChanges", relabelled))
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Reapplied label rule %s: %d changes labelled", config.LabelRuleID.ValueString(), relabelled),
+	})
+}
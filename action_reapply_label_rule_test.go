@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"golang.org/x/oauth2"
+)
+
+// invokeReapplyLabelRule validates and invokes the action directly, as
+// Terraform would for an action_trigger, and collects its progress messages.
+func invokeReapplyLabelRule(t *testing.T, serverURL string, config map[string]tftypes.Value) ([]string, action.InvokeResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	a := NewReapplyLabelRuleAction().(*reapplyLabelRuleAction)
+	var configureResp action.ConfigureResponse
+	a.Configure(ctx, action.ConfigureRequest{
+		ProviderData: testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL),
+	}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	for name := range schemaResp.Schema.Attributes {
+		if _, ok := config[name]; !ok {
+			config[name] = tftypes.NewValue(tftypes.String, nil)
+		}
+	}
+	tfConfig := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), config),
+	}
+
+	var validateResp action.ValidateConfigResponse
+	a.ValidateConfig(ctx, action.ValidateConfigRequest{Config: tfConfig}, &validateResp)
+	if validateResp.Diagnostics.HasError() {
+		return nil, action.InvokeResponse{Diagnostics: validateResp.Diagnostics}
+	}
+
+	var progress []string
+	resp := action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) { progress = append(progress, event.Message) },
+	}
+	a.Invoke(ctx, action.InvokeRequest{Config: tfConfig}, &resp)
+	return progress, resp
+}
+
+func TestReapplyLabelRuleAction_Invoke(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	mocks.changes.addChangeSummary("Resize RDS instance")
+	mocks.changes.addChangeSummary("Rotate IAM keys")
+	mocks.changes.addChangeSummary("Upgrade RDS engine")
+
+	createResp, err := mocks.labels.CreateLabelRule(context.Background(), connect.NewRequest(&sdp.CreateLabelRuleRequest{
+		Properties: &sdp.LabelRuleProperties{Name: "database", Colour: "#FF5733", Instructions: "rds"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleID := uuid.Must(uuid.FromBytes(createResp.Msg.GetRule().GetMetadata().GetLabelRuleUUID())).String()
+
+	progress, resp := invokeReapplyLabelRule(t, serverURL, map[string]tftypes.Value{
+		"label_rule_id": tftypes.NewValue(tftypes.String, ruleID),
+		"window":        tftypes.NewValue(tftypes.String, "168h"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Invoke: %v", resp.Diagnostics)
+	}
+	if len(progress) != 2 {
+		t.Fatalf("expected 2 progress messages, got %v", progress)
+	}
+	if !strings.HasSuffix(progress[1], ": 2 changes labelled") {
+		t.Errorf("unexpected result message: %q", progress[1])
+	}
+
+	// A window that ended before the changes were created labels nothing.
+	progress, resp = invokeReapplyLabelRule(t, serverURL, map[string]tftypes.Value{
+		"label_rule_id": tftypes.NewValue(tftypes.String, ruleID),
+		"end_at":        tftypes.NewValue(tftypes.String, time.Now().Add(-time.Hour).Format(time.RFC3339)),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Invoke: %v", resp.Diagnostics)
+	}
+	if !strings.HasSuffix(progress[len(progress)-1], ": 0 changes labelled") {
+		t.Errorf("unexpected result message: %q", progress[len(progress)-1])
+	}
+}
+
+func TestReapplyLabelRuleAction_Errors(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	_, resp := invokeReapplyLabelRule(t, serverURL, map[string]tftypes.Value{
+		"label_rule_id": tftypes.NewValue(tftypes.String, "0b4c8a5e-6f0a-4c3b-9a55-1f0e2d3c4b5a"),
+		"window":        tftypes.NewValue(tftypes.String, "30d"),
+	})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid reapply window" {
+		t.Errorf("expected invalid window error, got %v", resp.Diagnostics)
+	}
+
+	_, resp = invokeReapplyLabelRule(t, serverURL, map[string]tftypes.Value{
+		"label_rule_id": tftypes.NewValue(tftypes.String, "0b4c8a5e-6f0a-4c3b-9a55-1f0e2d3c4b5a"),
+	})
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Label rule not found" {
+		t.Errorf("expected not found error, got %v", resp.Diagnostics)
+	}
+}
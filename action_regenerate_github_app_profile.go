@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ action.Action              = (*regenerateGithubAppProfileAction)(nil)
+	_ action.ActionWithConfigure = (*regenerateGithubAppProfileAction)(nil)
+)
+
+type regenerateGithubAppProfileAction struct {
+	config sdpconnect.ConfigurationServiceClient
+}
+
+func NewRegenerateGithubAppProfileAction() action.Action {
+	return &regenerateGithubAppProfileAction{}
+}
+
+func (a *regenerateGithubAppProfileAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regenerate_github_app_profile"
+}
+
+func (a *regenerateGithubAppProfileAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "Regenerates the GitHub organisation profile that Overmind derives from repository history, " +
+			"such as the primary branch name and when changes are usually made. Trigger it from the " +
+			"overmind_github_app_installation's lifecycle action_trigger, on after_create or after_update, " +
+			"to refresh the profile once the app is connected.",
+		Attributes: map[string]actionschema.Attribute{},
+	}
+}
+
+func (a *regenerateGithubAppProfileAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	a.config = clients.Configuration
+}
+
+func (a *regenerateGithubAppProfileAction) Invoke(ctx context.Context, _ action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "RegenerateGithubAppProfile Invoke")
+	defer span.End()
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: "Regenerating GitHub organisation profile",
+	})
+
+	regenResp, err := a.config.RegenerateGithubAppProfile(ctx, connect.NewRequest(&sdp.RegenerateGithubAppProfileRequest{}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound || connect.CodeOf(err) == connect.CodeFailedPrecondition {
+			resp.Diagnostics.AddError("GitHub app not installed",
+				fmt.Sprintf("The GitHub organisation profile can only be regenerated once the GitHub app is installed: %s", err))
+		} else {
+			resp.Diagnostics.AddError("Failed to regenerate GitHub organisation profile", err.Error())
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "RegenerateGithubAppProfile failed")
+		return
+	}
+
+	branch := regenResp.Msg.GetGithubOrganisationProfile().GetPrimaryBranchName()
+	span.SetAttributes(attribute.String("ovm.githubApp.primaryBranch", branch))
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Regenerated GitHub organisation profile: primary branch %q", branch),
+	})
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultChangeSnapshotTimeout is how long starting or ending a change may
+// take, including the snapshot, when no timeout is configured.
+const defaultChangeSnapshotTimeout = 10 * time.Minute
+
+var (
+	_ action.Action                   = (*startChangeAction)(nil)
+	_ action.ActionWithConfigure      = (*startChangeAction)(nil)
+	_ action.ActionWithValidateConfig = (*startChangeAction)(nil)
+)
+
+type startChangeAction struct {
+	changes sdpconnect.ChangesServiceClient
+}
+
+// changeSnapshotActionModel is the configuration of the start and end change
+// actions.
+type changeSnapshotActionModel struct {
+	ChangeID types.String         `tfsdk:"change_id"`
+	Timeouts *invokeTimeoutsModel `tfsdk:"timeouts"`
+}
+
+func NewStartChangeAction() action.Action {
+	return &startChangeAction{}
+}
+
+func (a *startChangeAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_start_change"
+}
+
+func (a *startChangeAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "Starts a change, taking the snapshot of its blast radius that Overmind compares against " +
+			"once the change ends. Trigger it from the lifecycle action_trigger of the resources being changed, " +
+			"on before_create or before_update, and pair it with overmind_end_change on after_create or " +
+			"after_update. Waits up to timeouts.invoke, 10 minutes by default, for the snapshot to be saved.",
+		Attributes: changeSnapshotActionAttributes("start"),
+		Blocks: map[string]actionschema.Block{
+			"timeouts": invokeTimeoutsBlock(),
+		},
+	}
+}
+
+// changeSnapshotActionAttributes returns the schema attributes shared by the
+// start and end change actions. How long to wait for the snapshot is set with
+// timeouts.invoke, which defaults to defaultChangeSnapshotTimeout.
+func changeSnapshotActionAttributes(verb string) map[string]actionschema.Attribute {
+	return map[string]actionschema.Attribute{
+		"change_id": actionschema.StringAttribute{
+			Description: fmt.Sprintf("UUID of the change to %s, usually overmind_change.<name>.id.", verb),
+			Required:    true,
+		},
+	}
+}
+
+func (a *startChangeAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var config changeSnapshotActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(config.validate()...)
+}
+
+func (m *changeSnapshotActionModel) validate() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !m.ChangeID.IsNull() && !m.ChangeID.IsUnknown() {
+		if _, err := uuidToBytes(m.ChangeID.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("change_id"), "Invalid change ID", err.Error())
+		}
+	}
+	diags.Append(m.Timeouts.validate()...)
+	return diags
+}
+
+func (a *startChangeAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	a.changes = clients.Changes
+}
+
+func (a *startChangeAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "StartChange Invoke")
+	defer span.End()
+
+	var config changeSnapshotActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.id", config.ChangeID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(config.ChangeID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid change ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	timeout, diags := config.Timeouts.InvokeTimeout(defaultChangeSnapshotTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	streamCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Starting change %s", config.ChangeID.ValueString()),
+	})
+
+	err = a.startChange(streamCtx, uuidBytes, func(msg *sdp.StartChangeResponse) {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Starting change %s: %s (%d items, %d edges)",
+				config.ChangeID.ValueString(), msg.GetState().ToMessage(), msg.GetNumItems(), msg.GetNumEdges()),
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.Append(changeSnapshotError(streamCtx, err, "start", config.ChangeID.ValueString(), timeout)...)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "StartChange failed")
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Started change %s", config.ChangeID.ValueString()),
+	})
+}
+
+// startChange calls StartChange and reports each state the stream sends,
+// returning once the snapshot is saved.
+func (a *startChangeAction) startChange(ctx context.Context, changeUUID []byte, report func(*sdp.StartChangeResponse)) error {
+	stream, err := a.changes.StartChange(ctx, connect.NewRequest(&sdp.StartChangeRequest{
+		ChangeUUID: changeUUID,
+	}))
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	state := sdp.StartChangeResponse_STATE_UNSPECIFIED
+	for stream.Receive() {
+		state = stream.Msg().GetState()
+		report(stream.Msg())
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	if state != sdp.StartChangeResponse_STATE_DONE {
+		return fmt.Errorf("stream ended while the snapshot was in state %s", state)
+	}
+	return nil
+}
+
+// changeSnapshotError turns an error from the StartChange or EndChange stream
+// into a diagnostic, telling timeouts and invalid changes apart. The server
+// sees the timeout too, so it may end the stream before ctx expires.
+func changeSnapshotError(ctx context.Context, err error, verb, changeID string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || connect.CodeOf(err) == connect.CodeDeadlineExceeded:
+		diags.AddError(fmt.Sprintf("Timed out trying to %s change", verb),
+			fmt.Sprintf("The snapshot for change %s was not finished after %s. Overmind keeps working on it; "+
+				"raise timeouts.invoke if large changes need longer.", changeID, timeout))
+	case connect.CodeOf(err) == connect.CodeNotFound:
+		diags.AddAttributeError(path.Root("change_id"), "Change not found",
+			fmt.Sprintf("No change with ID %s exists.", changeID))
+	case connect.CodeOf(err) == connect.CodeFailedPrecondition:
+		diags.AddAttributeError(path.Root("change_id"), fmt.Sprintf("Cannot %s change", verb),
+			fmt.Sprintf("Change %s is not in a state that allows it to %s: %s", changeID, verb, err))
+	default:
+		diags.AddError(fmt.Sprintf("Failed to %s change", verb), err.Error())
+	}
+	return diags
+}
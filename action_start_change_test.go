@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"golang.org/x/oauth2"
+)
+
+// moveChange checks that the change is in from and moves it to to, returning
+// whether the mock should stall the snapshot.
+func (m *mockChangesHandler) moveChange(changeUUID []byte, from, to sdp.ChangeStatus) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(changeUUID)
+	if err != nil {
+		return false, err
+	}
+	if change.GetMetadata().GetStatus() != from {
+		return false, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("change is %s", change.GetMetadata().GetStatus()))
+	}
+	change.Metadata.Status = to
+	return m.stallSnapshots, nil
+}
+
+func (m *mockChangesHandler) StartChange(ctx context.Context, req *connect.Request[sdp.StartChangeRequest], stream *connect.ServerStream[sdp.StartChangeResponse]) error {
+	stall, err := m.moveChange(req.Msg.GetChangeUUID(), sdp.ChangeStatus_CHANGE_STATUS_DEFINING, sdp.ChangeStatus_CHANGE_STATUS_HAPPENING)
+	if err != nil {
+		return err
+	}
+	for _, state := range []sdp.StartChangeResponse_State{
+		sdp.StartChangeResponse_STATE_TAKING_SNAPSHOT,
+		sdp.StartChangeResponse_STATE_SAVING_SNAPSHOT,
+		sdp.StartChangeResponse_STATE_DONE,
+	} {
+		if err := stream.Send(&sdp.StartChangeResponse{State: state, NumItems: 3, NumEdges: 2}); err != nil {
+			return err
+		}
+		if stall {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (m *mockChangesHandler) EndChange(ctx context.Context, req *connect.Request[sdp.EndChangeRequest], stream *connect.ServerStream[sdp.EndChangeResponse]) error {
+	stall, err := m.moveChange(req.Msg.GetChangeUUID(), sdp.ChangeStatus_CHANGE_STATUS_HAPPENING, sdp.ChangeStatus_CHANGE_STATUS_DONE)
+	if err != nil {
+		return err
+	}
+	for _, state := range []sdp.EndChangeResponse_State{
+		sdp.EndChangeResponse_STATE_TAKING_SNAPSHOT,
+		sdp.EndChangeResponse_STATE_SAVING_SNAPSHOT,
+		sdp.EndChangeResponse_STATE_DONE,
+	} {
+		if err := stream.Send(&sdp.EndChangeResponse{State: state, NumItems: 4, NumEdges: 3}); err != nil {
+			return err
+		}
+		if stall {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (m *mockChangesHandler) changeStatus(id string) sdp.ChangeStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.full[id].GetMetadata().GetStatus()
+}
+
+// invokeChangeSnapshotAction validates and invokes the start or end change
+// action directly, as Terraform would for an action_trigger, and collects its
+// progress messages. An empty timeout leaves the timeouts block out.
+func invokeChangeSnapshotAction(t *testing.T, a action.Action, serverURL, changeID, timeout string) ([]string, action.InvokeResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	var configureResp action.ConfigureResponse
+	a.(action.ActionWithConfigure).Configure(ctx, action.ConfigureRequest{
+		ProviderData: testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL),
+	}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object) //nolint:forcetypeassert // schemas are objects
+	timeouts := tftypes.NewValue(configType.AttributeTypes["timeouts"], nil)
+	if timeout != "" {
+		timeouts = tftypes.NewValue(configType.AttributeTypes["timeouts"], map[string]tftypes.Value{
+			"invoke": tftypes.NewValue(tftypes.String, timeout),
+		})
+	}
+	tfConfig := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+			"change_id": tftypes.NewValue(tftypes.String, changeID),
+			"timeouts":  timeouts,
+		}),
+	}
+
+	var validateResp action.ValidateConfigResponse
+	a.(action.ActionWithValidateConfig).ValidateConfig(ctx, action.ValidateConfigRequest{Config: tfConfig}, &validateResp)
+	if validateResp.Diagnostics.HasError() {
+		return nil, action.InvokeResponse{Diagnostics: validateResp.Diagnostics}
+	}
+
+	var progress []string
+	resp := action.InvokeResponse{
+		SendProgress: func(event action.InvokeProgressEvent) { progress = append(progress, event.Message) },
+	}
+	a.Invoke(ctx, action.InvokeRequest{Config: tfConfig}, &resp)
+	return progress, resp
+}
+
+func createDefiningChange(t *testing.T, mocks *mockServer) string {
+	t.Helper()
+	createResp, err := mocks.changes.CreateChange(context.Background(), connect.NewRequest(&sdp.CreateChangeRequest{
+		Properties: &sdp.ChangeProperties{Title: "Scale out checkout"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uuid.UUID(createResp.Msg.GetChange().GetMetadata().GetUUID()).String()
+}
+
+func TestChangeSnapshotActions_Invoke(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+	id := createDefiningChange(t, mocks)
+
+	progress, resp := invokeChangeSnapshotAction(t, NewStartChangeAction(), serverURL, id, "")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("start: %v", resp.Diagnostics)
+	}
+	want := []string{
+		"Starting change " + id,
+		"Starting change " + id + ": Snapshot is being taken (3 items, 2 edges)",
+		"Starting change " + id + ": Snapshot is being saved (3 items, 2 edges)",
+		"Starting change " + id + ": Everything is complete (3 items, 2 edges)",
+		"Started change " + id,
+	}
+	if strings.Join(progress, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected progress:\n%s", strings.Join(progress, "\n"))
+	}
+	if got := mocks.changes.changeStatus(id); got != sdp.ChangeStatus_CHANGE_STATUS_HAPPENING {
+		t.Errorf("expected the change to be happening, got %s", got)
+	}
+
+	// A started change cannot be started again.
+	_, resp = invokeChangeSnapshotAction(t, NewStartChangeAction(), serverURL, id, "")
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Cannot start change" {
+		t.Errorf("expected cannot start error, got %v", resp.Diagnostics)
+	}
+
+	progress, resp = invokeChangeSnapshotAction(t, NewEndChangeAction(), serverURL, id, "5m")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("end: %v", resp.Diagnostics)
+	}
+	if len(progress) != 5 || progress[4] != "Ended change "+id {
+		t.Errorf("unexpected progress: %v", progress)
+	}
+	if got := mocks.changes.changeStatus(id); got != sdp.ChangeStatus_CHANGE_STATUS_DONE {
+		t.Errorf("expected the change to be done, got %s", got)
+	}
+}
+
+func TestChangeSnapshotActions_Errors(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+	mocks.changes.stallSnapshots = true
+	id := createDefiningChange(t, mocks)
+
+	progress, resp := invokeChangeSnapshotAction(t, NewStartChangeAction(), serverURL, id, "100ms")
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Timed out trying to start change" {
+		t.Errorf("expected a timeout, got %v", resp.Diagnostics)
+	}
+	if len(progress) != 2 || !strings.HasSuffix(progress[1], ": Snapshot is being taken (3 items, 2 edges)") {
+		t.Errorf("expected progress up to the stalled snapshot, got %v", progress)
+	}
+
+	_, resp = invokeChangeSnapshotAction(t, NewEndChangeAction(), serverURL, uuid.NewString(), "")
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Change not found" {
+		t.Errorf("expected not found error, got %v", resp.Diagnostics)
+	}
+
+	_, resp = invokeChangeSnapshotAction(t, NewEndChangeAction(), serverURL, id, "1 hour")
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid timeout" {
+		t.Errorf("expected invalid timeout error, got %v", resp.Diagnostics)
+	}
+}
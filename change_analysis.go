@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultChangeAnalysisTimeout matches the longest Overmind lets a change
+// analysis run.
+const defaultChangeAnalysisTimeout = 30 * time.Minute
+
+// changeAnalysisPollInterval is how often GetChangeRisks is polled while
+// waiting for change analysis to finish. Tests shorten it.
+var changeAnalysisPollInterval = 5 * time.Second //nolint:gochecknoglobals // overridden in tests
+
+// changeAnalysisPickupGrace is how long after StartChangeAnalysis a finished
+// status is assumed to belong to the previous analysis, unless the new one
+// has been seen in progress. Tests shorten it.
+var changeAnalysisPickupGrace = 30 * time.Second //nolint:gochecknoglobals // overridden in tests
+
+// errChangeAnalysisFailed is returned when change analysis finishes with an
+// error.
+var errChangeAnalysisFailed = errors.New("change analysis failed")
+
+// waitForChangeAnalysis polls GetChangeRisks while the change's analysis is
+// in progress, and returns the risks once it is done, skipped or was never
+// started. If startedAt is set, an analysis was just started then, and until
+// it is seen in progress or changeAnalysisPickupGrace has passed, finished
+// statuses are those of the previous analysis and are ignored. As that
+// analysis has been asked for, an unspecified status then means it has not
+// started yet and is waited for until the timeout.
+func waitForChangeAnalysis(ctx context.Context, changes sdpconnect.ChangesServiceClient, changeUUID []byte, startedAt time.Time, timeout time.Duration) (*sdp.ChangeRiskMetadata, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "WaitForChangeAnalysis")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(changeAnalysisPollInterval)
+	defer ticker.Stop()
+
+	pickedUp := startedAt.IsZero()
+	var md *sdp.ChangeRiskMetadata
+	timedOut := func() error {
+		if !startedAt.IsZero() && md.GetChangeAnalysisStatus().GetStatus() == sdp.ChangeAnalysisStatus_STATUS_UNSPECIFIED {
+			return fmt.Errorf("timed out after %s waiting for change analysis to start", timeout)
+		}
+		return fmt.Errorf("timed out after %s waiting for change analysis to finish", timeout)
+	}
+	for {
+		risksResp, err := changes.GetChangeRisks(ctx, connect.NewRequest(&sdp.GetChangeRisksRequest{
+			UUID: changeUUID,
+		}))
+		if err != nil {
+			if ctx.Err() != nil {
+				err = timedOut()
+				span.SetStatus(codes.Error, "timeout")
+			} else {
+				err = fmt.Errorf("getting change risks: %w", err)
+				span.SetStatus(codes.Error, "GetChangeRisks failed")
+			}
+			span.RecordError(err)
+			return nil, err
+		}
+
+		md = risksResp.Msg.GetChangeRiskMetadata()
+		status := md.GetChangeAnalysisStatus().GetStatus()
+		span.SetAttributes(attribute.String("ovm.change.analysisStatus", status.String()))
+
+		switch {
+		case status == sdp.ChangeAnalysisStatus_STATUS_INPROGRESS:
+			pickedUp = true
+		case !pickedUp && time.Since(startedAt) < changeAnalysisPickupGrace:
+			// Overmind has not picked up the new analysis yet, keep waiting.
+		case status == sdp.ChangeAnalysisStatus_STATUS_UNSPECIFIED && !startedAt.IsZero():
+			// The analysis that was started has not been picked up at all.
+		case status == sdp.ChangeAnalysisStatus_STATUS_ERROR:
+			span.RecordError(errChangeAnalysisFailed)
+			span.SetStatus(codes.Error, "analysis failed")
+			return md, errChangeAnalysisFailed
+		default:
+			return md, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			err := timedOut()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "timeout")
+			return md, err
+		case <-ticker.C:
+		}
+	}
+}
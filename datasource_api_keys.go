@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ datasource.DataSource                   = (*apiKeysDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*apiKeysDataSource)(nil)
+)
+
+type apiKeysDataSource struct {
+	apiKeys sdpconnect.ApiKeyServiceClient
+}
+
+type apiKeysDataSourceModel struct {
+	UnusedForDays types.Int64          `tfsdk:"unused_for_days"`
+	HasScope      types.String         `tfsdk:"has_scope"`
+	Status        types.String         `tfsdk:"status"`
+	IDs           []string             `tfsdk:"ids"`
+	Keys          []apiKeySummaryModel `tfsdk:"keys"`
+}
+
+type apiKeySummaryModel struct {
+	ID       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Scopes   []string     `tfsdk:"scopes"`
+	Status   types.String `tfsdk:"status"`
+	Error    types.String `tfsdk:"error"`
+	Created  types.String `tfsdk:"created"`
+	LastUsed types.String `tfsdk:"last_used"`
+}
+
+func NewAPIKeysDataSource() datasource.DataSource {
+	return &apiKeysDataSource{}
+}
+
+func (d *apiKeysDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_keys"
+}
+
+func (d *apiKeysDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Lists the API keys in the current Overmind account, optionally filtered to stale keys " +
+			"or keys with a given scope. Use it in check blocks to audit API keys.",
+		Attributes: map[string]dsschema.Attribute{
+			"unused_for_days": dsschema.Int64Attribute{
+				Description: "Only return keys that have not been used for at least this many days. " +
+					"Keys that have never been used count from when they were created.",
+				Optional: true,
+			},
+			"has_scope": dsschema.StringAttribute{
+				Description: "Only return keys granted this scope, e.g. \"account:write\".",
+				Optional:    true,
+			},
+			"status": dsschema.StringAttribute{
+				Description: "Only return keys with this status, e.g. \"KEY_STATUS_READY\".",
+				Optional:    true,
+			},
+			"ids": dsschema.ListAttribute{
+				Description: "UUIDs of the matching keys, in the same order as keys.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"keys": dsschema.ListNestedAttribute{
+				Description: "Matching keys, ordered by name. The keys themselves are never returned.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "API key UUID.",
+							Computed:    true,
+						},
+						"name": dsschema.StringAttribute{
+							Description: "API key name.",
+							Computed:    true,
+						},
+						"scopes": dsschema.ListAttribute{
+							Description: "Scopes granted to the key, sorted.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"status": dsschema.StringAttribute{
+							Description: "Key status, e.g. KEY_STATUS_READY or KEY_STATUS_REVOKED.",
+							Computed:    true,
+						},
+						"error": dsschema.StringAttribute{
+							Description: "Error reported while authorizing the key, if status is KEY_STATUS_ERROR.",
+							Computed:    true,
+						},
+						"created": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of when the key was created.",
+							Computed:    true,
+						},
+						"last_used": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of when the key was last used, or null if it never has been.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *apiKeysDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config apiKeysDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.UnusedForDays.IsNull() && !config.UnusedForDays.IsUnknown() && config.UnusedForDays.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("unused_for_days"), "Invalid number of days",
+			fmt.Sprintf("unused_for_days must be at least 1, got %d.", config.UnusedForDays.ValueInt64()))
+	}
+
+	if !config.HasScope.IsNull() && !config.HasScope.IsUnknown() && !slices.Contains(knownAPIKeyScopes, config.HasScope.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("has_scope"), "Invalid API key scope",
+			fmt.Sprintf("%q is not a known scope. Valid scopes are %s.", config.HasScope.ValueString(), strings.Join(knownAPIKeyScopes, ", ")))
+	}
+
+	if !config.Status.IsNull() && !config.Status.IsUnknown() {
+		if _, ok := sdp.KeyStatus_value[config.Status.ValueString()]; !ok {
+			valid := make([]string, 0, len(sdp.KeyStatus_value))
+			for name := range sdp.KeyStatus_value {
+				valid = append(valid, name)
+			}
+			slices.Sort(valid)
+			resp.Diagnostics.AddAttributeError(path.Root("status"), "Invalid API key status",
+				fmt.Sprintf("status must be one of %s, got %q.", strings.Join(valid, ", "), config.Status.ValueString()))
+		}
+	}
+}
+
+func (d *apiKeysDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.apiKeys = clients.APIKeys
+}
+
+func (d *apiKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKeys Read")
+	defer span.End()
+
+	var config apiKeysDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	listResp, err := d.apiKeys.ListAPIKeys(ctx, connect.NewRequest(&sdp.ListAPIKeysRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list API keys", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListAPIKeys failed")
+		return
+	}
+
+	var unusedSince time.Time
+	if !config.UnusedForDays.IsNull() {
+		unusedSince = time.Now().AddDate(0, 0, -int(config.UnusedForDays.ValueInt64()))
+	}
+
+	config.Keys = []apiKeySummaryModel{}
+	for _, key := range listResp.Msg.GetKeys() {
+		md := key.GetMetadata()
+		if !config.HasScope.IsNull() && !slices.Contains(md.GetScopes(), config.HasScope.ValueString()) {
+			continue
+		}
+		if !config.Status.IsNull() && md.GetStatus().String() != config.Status.ValueString() {
+			continue
+		}
+		if !unusedSince.IsZero() {
+			lastActivity := md.GetCreated()
+			if md.GetLastUsed() != nil {
+				lastActivity = md.GetLastUsed()
+			}
+			if lastActivity.AsTime().After(unusedSince) {
+				continue
+			}
+		}
+
+		id, err := uuid.FromBytes(md.GetUuid())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse API key UUID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "UUID parse failed")
+			return
+		}
+
+		scopes := slices.Clone(nonNilStrings(md.GetScopes()))
+		slices.Sort(scopes)
+
+		config.Keys = append(config.Keys, apiKeySummaryModel{
+			ID:       types.StringValue(id.String()),
+			Name:     types.StringValue(key.GetProperties().GetName()),
+			Scopes:   scopes,
+			Status:   types.StringValue(md.GetStatus().String()),
+			Error:    stringOrNull(md.GetError()),
+			Created:  timestampString(md.GetCreated()),
+			LastUsed: timestampString(md.GetLastUsed()),
+		})
+	}
+
+	slices.SortFunc(config.Keys, func(a, b apiKeySummaryModel) int {
+		if c := strings.Compare(a.Name.ValueString(), b.Name.ValueString()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.ValueString(), b.ID.ValueString())
+	})
+
+	config.IDs = make([]string, len(config.Keys))
+	for i, key := range config.Keys {
+		config.IDs[i] = key.ID.ValueString()
+	}
+
+	span.SetAttributes(
+		attribute.Int("ovm.apiKeys.total", len(listResp.Msg.GetKeys())),
+		attribute.Int("ovm.apiKeys.matched", len(config.Keys)),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// addKey stores a key directly in the mock, as if it had been created
+// outside of Terraform.
+func (m *mockAPIKeyHandler) addKey(name string, scopes []string, created, lastUsed time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := m.newKey(name, scopes).GetKey()
+	key.Metadata.Status = sdp.KeyStatus_KEY_STATUS_READY
+	key.Metadata.Created = timestamppb.New(created)
+	if !lastUsed.IsZero() {
+		key.Metadata.LastUsed = timestamppb.New(lastUsed)
+	}
+}
+
+func TestAPIKeysDataSource_Filters(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	now := time.Now()
+	mocks.apiKeys.addKey("active-ci", []string{"changes:write"}, now.AddDate(0, -6, 0), now.Add(-time.Hour))
+	mocks.apiKeys.addKey("stale-admin", []string{"account:write", "changes:write"}, now.AddDate(-1, 0, 0), now.AddDate(0, -4, 0))
+	mocks.apiKeys.addKey("never-used", []string{"explore:read"}, now.AddDate(0, -3, 0), time.Time{})
+	mocks.apiKeys.addKey("brand-new", []string{"explore:read"}, now.Add(-time.Hour), time.Time{})
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `
+data "overmind_api_keys" "all" {}
+
+data "overmind_api_keys" "stale" {
+  unused_for_days = 30
+}
+
+data "overmind_api_keys" "admin" {
+  has_scope = "account:write"
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.all", "keys.#", "4"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.all", "keys.0.name", "active-ci"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.stale", "keys.#", "2"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.stale", "keys.0.name", "never-used"),
+					tfresource.TestCheckNoResourceAttr("data.overmind_api_keys.stale", "keys.0.last_used"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.stale", "keys.1.name", "stale-admin"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.admin", "keys.#", "1"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.admin", "keys.0.scopes.#", "2"),
+					tfresource.TestCheckResourceAttr("data.overmind_api_keys.admin", "keys.0.scopes.0", "account:write"),
+				),
+			},
+			{
+				Config: `data "overmind_api_keys" "bad" {
+  unused_for_days = 0
+}`,
+				ExpectError: regexp.MustCompile(`Invalid number of days`),
+			},
+		},
+	})
+}
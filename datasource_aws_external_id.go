@@ -50,13 +50,13 @@ func (d *awsExternalIdDataSource) Configure(_ context.Context, req datasource.Co
 	if req.ProviderData == nil {
 		return
 	}
-	mgmt, ok := req.ProviderData.(sdpconnect.ManagementServiceClient)
+	clients, ok := req.ProviderData.(*overmindClients)
 	if !ok {
 		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
-			fmt.Sprintf("Expected sdpconnect.ManagementServiceClient, got %T", req.ProviderData))
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
 		return
 	}
-	d.mgmt = mgmt
+	d.mgmt = clients.Management
 }
 
 func (d *awsExternalIdDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
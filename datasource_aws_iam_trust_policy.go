@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// externalIDConditionKey is the condition that ties the trust policy to the
+// Overmind account's external ID. It is always present and cannot be
+// overridden by extra conditions.
+const externalIDConditionKey = "sts:ExternalId"
+
+var (
+	_ datasource.DataSource                   = (*awsIAMTrustPolicyDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*awsIAMTrustPolicyDataSource)(nil)
+)
+
+type awsIAMTrustPolicyDataSource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type awsIAMTrustPolicyDataSourceModel struct {
+	OvermindPrincipalARN types.String                `tfsdk:"overmind_principal_arn"`
+	ExternalID           types.String                `tfsdk:"external_id"`
+	Conditions           []trustPolicyConditionModel `tfsdk:"condition"`
+	JSON                 types.String                `tfsdk:"json"`
+}
+
+type trustPolicyConditionModel struct {
+	Test     types.String `tfsdk:"test"`
+	Variable types.String `tfsdk:"variable"`
+	Values   []string     `tfsdk:"values"`
+}
+
+func NewAWSIAMTrustPolicyDataSource() datasource.DataSource {
+	return &awsIAMTrustPolicyDataSource{}
+}
+
+func (d *awsIAMTrustPolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_iam_trust_policy"
+}
+
+func (d *awsIAMTrustPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Renders the IAM trust policy that lets Overmind assume a role in your AWS account. " +
+			"Pass json to aws_iam_role.assume_role_policy, then reference the role from overmind_aws_source.",
+		Attributes: map[string]dsschema.Attribute{
+			"overmind_principal_arn": dsschema.StringAttribute{
+				Description: "ARN of the Overmind AWS principal allowed to assume the role. " +
+					"Overmind does not expose it through its API, so it has to be set explicitly.",
+				Required: true,
+			},
+			"external_id": dsschema.StringAttribute{
+				Description: "AWS STS external ID required by the policy, stable per Overmind account.",
+				Computed:    true,
+			},
+			"json": dsschema.StringAttribute{
+				Description: "The trust policy as a JSON document.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]dsschema.Block{
+			"condition": dsschema.ListNestedBlock{
+				Description: "Extra conditions to add to the policy statement, alongside the external ID check.",
+				NestedObject: dsschema.NestedBlockObject{
+					Attributes: map[string]dsschema.Attribute{
+						"test": dsschema.StringAttribute{
+							Description: "IAM condition operator, e.g. \"StringLike\" or \"ArnEquals\".",
+							Required:    true,
+						},
+						"variable": dsschema.StringAttribute{
+							Description: "Condition key, e.g. \"aws:SourceIp\".",
+							Required:    true,
+						},
+						"values": dsschema.ListAttribute{
+							Description: "Values to compare the condition key against. Any one must match.",
+							Required:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *awsIAMTrustPolicyDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config awsIAMTrustPolicyDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool, len(config.Conditions))
+	for i, condition := range config.Conditions {
+		if condition.Test.IsUnknown() || condition.Variable.IsUnknown() {
+			continue
+		}
+		attrPath := path.Root("condition").AtListIndex(i)
+		key := condition.Test.ValueString() + " " + condition.Variable.ValueString()
+		if condition.Test.ValueString() == "StringEquals" && condition.Variable.ValueString() == externalIDConditionKey {
+			resp.Diagnostics.AddAttributeError(attrPath.AtName("variable"), "Conflicting trust policy condition",
+				"The StringEquals sts:ExternalId condition is always set to the Overmind external ID and cannot be overridden.")
+			continue
+		}
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(attrPath, "Duplicate trust policy condition",
+				fmt.Sprintf("More than one condition uses %s on %s. Combine their values into one condition block.",
+					condition.Test.ValueString(), condition.Variable.ValueString()))
+		}
+		seen[key] = true
+		if len(condition.Values) == 0 {
+			resp.Diagnostics.AddAttributeError(attrPath.AtName("values"), "Missing condition values",
+				"A trust policy condition needs at least one value.")
+		}
+	}
+}
+
+func (d *awsIAMTrustPolicyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.mgmt = clients.Management
+}
+
+func (d *awsIAMTrustPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AWSIAMTrustPolicy Read")
+	defer span.End()
+
+	var config awsIAMTrustPolicyDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.aws.principal", config.OvermindPrincipalARN.ValueString()))
+
+	extIDResp, err := d.mgmt.GetOrCreateAWSExternalId(ctx,
+		connect.NewRequest(&sdp.GetOrCreateAWSExternalIdRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get AWS external ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetOrCreateAWSExternalId failed")
+		return
+	}
+	config.ExternalID = types.StringValue(extIDResp.Msg.GetAwsExternalId())
+
+	policy, err := renderTrustPolicy(config.OvermindPrincipalARN.ValueString(), config.ExternalID.ValueString(), config.Conditions)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render trust policy", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "render failed")
+		return
+	}
+	config.JSON = types.StringValue(policy)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+type iamPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []iamPolicyStatement `json:"Statement"`
+}
+
+type iamPolicyStatement struct {
+	Effect    string                    `json:"Effect"`
+	Principal map[string]string         `json:"Principal"`
+	Action    string                    `json:"Action"`
+	Condition map[string]map[string]any `json:"Condition"`
+}
+
+// renderTrustPolicy builds the trust policy JSON. Like aws_iam_policy_document,
+// single-value conditions are rendered as a string rather than a list.
+func renderTrustPolicy(principal, externalID string, conditions []trustPolicyConditionModel) (string, error) {
+	condition := map[string]map[string]any{
+		"StringEquals": {externalIDConditionKey: externalID},
+	}
+	for _, c := range conditions {
+		test := c.Test.ValueString()
+		if condition[test] == nil {
+			condition[test] = map[string]any{}
+		}
+		if len(c.Values) == 1 {
+			condition[test][c.Variable.ValueString()] = c.Values[0]
+		} else {
+			condition[test][c.Variable.ValueString()] = c.Values
+		}
+	}
+
+	b, err := json.MarshalIndent(iamPolicyDocument{
+		Version: "2012-10-17",
+		Statement: []iamPolicyStatement{{
+			Effect:    "Allow",
+			Principal: map[string]string{"AWS": principal},
+			Action:    "sts:AssumeRole",
+			Condition: condition,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
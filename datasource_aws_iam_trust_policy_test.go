@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRenderTrustPolicy(t *testing.T) {
+	policy, err := renderTrustPolicy("arn:aws:iam::111111111111:root", "ext-123", []trustPolicyConditionModel{
+		{Test: types.StringValue("StringLike"), Variable: types.StringValue("aws:PrincipalArn"), Values: []string{"arn:aws:iam::111111111111:role/a"}},
+		{Test: types.StringValue("IpAddress"), Variable: types.StringValue("aws:SourceIp"), Values: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(policy), &got); err != nil {
+		t.Fatalf("policy is not valid JSON: %v", err)
+	}
+	want := map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []any{map[string]any{
+			"Effect":    "Allow",
+			"Principal": map[string]any{"AWS": "arn:aws:iam::111111111111:root"},
+			"Action":    "sts:AssumeRole",
+			"Condition": map[string]any{
+				"StringEquals": map[string]any{"sts:ExternalId": "ext-123"},
+				"StringLike":   map[string]any{"aws:PrincipalArn": "arn:aws:iam::111111111111:role/a"},
+				"IpAddress":    map[string]any{"aws:SourceIp": []any{"10.0.0.0/8", "192.168.0.0/16"}},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected policy:\n%s", policy)
+	}
+}
+
+func TestAWSIAMTrustPolicyDataSource_Read(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `
+data "overmind_aws_iam_trust_policy" "default" {
+  overmind_principal_arn = "arn:aws:iam::111111111111:root"
+}
+
+data "overmind_aws_iam_trust_policy" "custom" {
+  overmind_principal_arn = "arn:aws:iam::222222222222:root"
+
+  condition {
+    test     = "IpAddress"
+    variable = "aws:SourceIp"
+    values   = ["10.0.0.0/8"]
+  }
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_aws_iam_trust_policy.default", "external_id", "test-external-id-12345"),
+					tfresource.TestMatchResourceAttr("data.overmind_aws_iam_trust_policy.default", "json",
+						regexp.MustCompile(`"AWS": "arn:aws:iam::111111111111:root"`)),
+					tfresource.TestMatchResourceAttr("data.overmind_aws_iam_trust_policy.default", "json",
+						regexp.MustCompile(`"sts:ExternalId": "test-external-id-12345"`)),
+					tfresource.TestMatchResourceAttr("data.overmind_aws_iam_trust_policy.custom", "json",
+						regexp.MustCompile(`"aws:SourceIp": "10.0.0.0/8"`)),
+				),
+			},
+			{
+				// There is no default principal to fall back to.
+				Config:      `data "overmind_aws_iam_trust_policy" "default" {}`,
+				ExpectError: regexp.MustCompile(`The argument "overmind_principal_arn" is required`),
+			},
+			{
+				Config: `
+data "overmind_aws_iam_trust_policy" "bad" {
+  overmind_principal_arn = "arn:aws:iam::111111111111:root"
+
+  condition {
+    test     = "StringEquals"
+    variable = "sts:ExternalId"
+    values   = ["something-else"]
+  }
+}`,
+				ExpectError: regexp.MustCompile(`Conflicting trust policy condition`),
+			},
+		},
+	})
+}
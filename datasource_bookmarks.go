@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var _ datasource.DataSource = (*bookmarksDataSource)(nil)
+
+type bookmarksDataSource struct {
+	bookmarks sdpconnect.BookmarksServiceClient
+}
+
+type bookmarksDataSourceModel struct {
+	Name          types.String           `tfsdk:"name"`
+	IncludeSystem types.Bool             `tfsdk:"include_system"`
+	IDs           []string               `tfsdk:"ids"`
+	Bookmarks     []bookmarkSummaryModel `tfsdk:"bookmarks"`
+}
+
+type bookmarkSummaryModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	IsSystem    types.Bool   `tfsdk:"is_system"`
+	Created     types.String `tfsdk:"created"`
+	Queries     []queryModel `tfsdk:"queries"`
+}
+
+func NewBookmarksDataSource() datasource.DataSource {
+	return &bookmarksDataSource{}
+}
+
+func (d *bookmarksDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bookmarks"
+}
+
+func (d *bookmarksDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Lists the bookmarks in the current Overmind account, optionally filtered by name.",
+		Attributes: map[string]dsschema.Attribute{
+			"name": dsschema.StringAttribute{
+				Description: "Only return bookmarks with exactly this name.",
+				Optional:    true,
+			},
+			"include_system": dsschema.BoolAttribute{
+				Description: "Whether to include bookmarks created by Overmind itself. Defaults to false.",
+				Optional:    true,
+			},
+			"ids": dsschema.ListAttribute{
+				Description: "UUIDs of the matching bookmarks, in the same order as bookmarks.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"bookmarks": dsschema.ListNestedAttribute{
+				Description: "Matching bookmarks, ordered by name.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "Bookmark UUID.",
+							Computed:    true,
+						},
+						"name": dsschema.StringAttribute{
+							Description: "Bookmark name.",
+							Computed:    true,
+						},
+						"description": dsschema.StringAttribute{
+							Description: "Bookmark description.",
+							Computed:    true,
+						},
+						"is_system": dsschema.BoolAttribute{
+							Description: "Whether the bookmark was created by Overmind itself.",
+							Computed:    true,
+						},
+						"created": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of when the bookmark was created.",
+							Computed:    true,
+						},
+						"queries": queryDataSourceAttribute("Queries saved in the bookmark."),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *bookmarksDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.bookmarks = clients.Bookmarks
+}
+
+func (d *bookmarksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmarks Read")
+	defer span.End()
+
+	var config bookmarksDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	listResp, err := d.bookmarks.ListBookmarks(ctx, connect.NewRequest(&sdp.ListBookmarksRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list bookmarks", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListBookmarks failed")
+		return
+	}
+
+	config.Bookmarks = []bookmarkSummaryModel{}
+	for _, bookmark := range listResp.Msg.GetBookmarks() {
+		props := bookmark.GetProperties()
+		if !config.Name.IsNull() && props.GetName() != config.Name.ValueString() {
+			continue
+		}
+		if props.GetIsSystem() && !config.IncludeSystem.ValueBool() {
+			continue
+		}
+
+		id, err := uuid.FromBytes(bookmark.GetMetadata().GetUUID())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse bookmark UUID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "UUID parse failed")
+			return
+		}
+
+		config.Bookmarks = append(config.Bookmarks, bookmarkSummaryModel{
+			ID:          types.StringValue(id.String()),
+			Name:        types.StringValue(props.GetName()),
+			Description: stringOrNull(props.GetDescription()),
+			IsSystem:    types.BoolValue(props.GetIsSystem()),
+			Created:     timestampString(bookmark.GetMetadata().GetCreated()),
+			Queries:     queryModelsFromSDP(props.GetQueries()),
+		})
+	}
+
+	slices.SortFunc(config.Bookmarks, func(a, b bookmarkSummaryModel) int {
+		if c := strings.Compare(a.Name.ValueString(), b.Name.ValueString()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.ValueString(), b.ID.ValueString())
+	})
+
+	config.IDs = make([]string, len(config.Bookmarks))
+	for i, bookmark := range config.Bookmarks {
+		config.IDs[i] = bookmark.ID.ValueString()
+	}
+
+	span.SetAttributes(
+		attribute.Int("ovm.bookmarks.total", len(listResp.Msg.GetBookmarks())),
+		attribute.Int("ovm.bookmarks.matched", len(config.Bookmarks)),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
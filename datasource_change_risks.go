@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// riskSeverities are the severities fail_on_severity accepts, lowest first.
+var riskSeverities = []string{ //nolint:gochecknoglobals // constant list
+	sdp.Risk_SEVERITY_LOW.String(),
+	sdp.Risk_SEVERITY_MEDIUM.String(),
+	sdp.Risk_SEVERITY_HIGH.String(),
+}
+
+var (
+	_ datasource.DataSource                   = (*changeRisksDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*changeRisksDataSource)(nil)
+)
+
+type changeRisksDataSource struct {
+	changes sdpconnect.ChangesServiceClient
+}
+
+type changeRisksDataSourceModel struct {
+	ChangeID        types.String       `tfsdk:"change_id"`
+	TicketLink      types.String       `tfsdk:"ticket_link"`
+	FailOnSeverity  types.String       `tfsdk:"fail_on_severity"`
+	AllowUnanalysed types.Bool         `tfsdk:"allow_unanalysed"`
+	Title           types.String       `tfsdk:"title"`
+	AnalysisStatus  types.String       `tfsdk:"analysis_status"`
+	LowRiskCount    types.Int64        `tfsdk:"low_risk_count"`
+	MediumRiskCount types.Int64        `tfsdk:"medium_risk_count"`
+	HighRiskCount   types.Int64        `tfsdk:"high_risk_count"`
+	Risks           []riskModel        `tfsdk:"risks"`
+	Timeouts        *readTimeoutsModel `tfsdk:"timeouts"`
+}
+
+type riskModel struct {
+	ID           types.String       `tfsdk:"id"`
+	Title        types.String       `tfsdk:"title"`
+	Severity     types.String       `tfsdk:"severity"`
+	Description  types.String       `tfsdk:"description"`
+	RelatedItems []relatedItemModel `tfsdk:"related_items"`
+}
+
+type relatedItemModel struct {
+	GloballyUniqueName   types.String `tfsdk:"globally_unique_name"`
+	Type                 types.String `tfsdk:"type"`
+	Scope                types.String `tfsdk:"scope"`
+	UniqueAttributeValue types.String `tfsdk:"unique_attribute_value"`
+}
+
+func NewChangeRisksDataSource() datasource.DataSource {
+	return &changeRisksDataSource{}
+}
+
+func (d *changeRisksDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_change_risks"
+}
+
+func (d *changeRisksDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Loads the risks Overmind found for a change, waiting for change analysis to finish if it is " +
+			"still in progress. Set fail_on_severity to stop the run when the change is too risky.",
+		Attributes: map[string]dsschema.Attribute{
+			"change_id": dsschema.StringAttribute{
+				Description: "UUID of the change. Exactly one of change_id and ticket_link must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"ticket_link": dsschema.StringAttribute{
+				Description: "Ticket link of the change, as set on overmind_change.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"fail_on_severity": dsschema.StringAttribute{
+				Description: fmt.Sprintf("Fail with an error if the change has any risks of this severity or higher, "+
+					"or has no analysis results: one of %s.", strings.Join(riskSeverities, ", ")),
+				Optional: true,
+			},
+			"allow_unanalysed": dsschema.BoolAttribute{
+				Description: "Let fail_on_severity pass a change that has no analysis results because its analysis " +
+					"was skipped or never run. By default such changes fail, since their risks are unknown.",
+				Optional: true,
+			},
+			"title": dsschema.StringAttribute{
+				Description: "Title of the change.",
+				Computed:    true,
+			},
+			"analysis_status": dsschema.StringAttribute{
+				Description: "Status of the change analysis: STATUS_DONE, STATUS_SKIPPED, or STATUS_UNSPECIFIED " +
+					"if the change has not been analysed.",
+				Computed: true,
+			},
+			"low_risk_count": dsschema.Int64Attribute{
+				Description: "Number of low severity risks.",
+				Computed:    true,
+			},
+			"medium_risk_count": dsschema.Int64Attribute{
+				Description: "Number of medium severity risks.",
+				Computed:    true,
+			},
+			"high_risk_count": dsschema.Int64Attribute{
+				Description: "Number of high severity risks.",
+				Computed:    true,
+			},
+			"risks": dsschema.ListNestedAttribute{
+				Description: "Risks found for the change, in the order the API returns them.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "UUID of the risk.",
+							Computed:    true,
+						},
+						"title": dsschema.StringAttribute{
+							Description: "Short title of the risk.",
+							Computed:    true,
+						},
+						"severity": dsschema.StringAttribute{
+							Description: "Severity of the risk: SEVERITY_LOW, SEVERITY_MEDIUM or SEVERITY_HIGH.",
+							Computed:    true,
+						},
+						"description": dsschema.StringAttribute{
+							Description: "Explanation of the risk.",
+							Computed:    true,
+						},
+						"related_items": dsschema.ListNestedAttribute{
+							Description: "Items the risk relates to.",
+							Computed:    true,
+							NestedObject: dsschema.NestedAttributeObject{
+								Attributes: map[string]dsschema.Attribute{
+									"globally_unique_name": dsschema.StringAttribute{
+										Description: "Scope, type and unique attribute value of the item, joined with dots.",
+										Computed:    true,
+									},
+									"type": dsschema.StringAttribute{
+										Description: "Item type, e.g. \"ec2-instance\".",
+										Computed:    true,
+									},
+									"scope": dsschema.StringAttribute{
+										Description: "Scope the item is in.",
+										Computed:    true,
+									},
+									"unique_attribute_value": dsschema.StringAttribute{
+										Description: "Value of the item's unique attribute.",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Blocks: map[string]dsschema.Block{
+			"timeouts": readTimeoutsBlock(),
+		},
+	}
+}
+
+func (d *changeRisksDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config changeRisksDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.ChangeID.IsUnknown() && !config.TicketLink.IsUnknown() {
+		if config.ChangeID.IsNull() == config.TicketLink.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("change_id"), "Invalid change lookup",
+				"Exactly one of change_id and ticket_link must be set.")
+		} else if !config.ChangeID.IsNull() {
+			if _, err := uuidToBytes(config.ChangeID.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("change_id"), "Invalid change ID", err.Error())
+			}
+		}
+	}
+
+	if !config.FailOnSeverity.IsNull() && !config.FailOnSeverity.IsUnknown() {
+		if _, ok := parseRiskSeverity(config.FailOnSeverity.ValueString()); !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("fail_on_severity"), "Invalid severity",
+				fmt.Sprintf("fail_on_severity must be one of %s, got %q.",
+					strings.Join(riskSeverities, ", "), config.FailOnSeverity.ValueString()))
+		}
+	}
+
+	resp.Diagnostics.Append(config.Timeouts.validate()...)
+}
+
+func (d *changeRisksDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.changes = clients.Changes
+}
+
+func (d *changeRisksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "ChangeRisks DataSource Read")
+	defer span.End()
+
+	var config changeRisksDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	change, diags := d.findChange(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "change lookup failed")
+		return
+	}
+
+	changeID, err := uuid.FromBytes(change.GetMetadata().GetUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse change UUID", err.Error())
+		return
+	}
+	config.ChangeID = types.StringValue(changeID.String())
+	config.TicketLink = stringOrNull(change.GetProperties().GetTicketLink())
+	config.Title = types.StringValue(change.GetProperties().GetTitle())
+	span.SetAttributes(attribute.String("ovm.change.id", config.ChangeID.ValueString()))
+
+	timeout, diags := config.Timeouts.ReadTimeout(defaultChangeAnalysisTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	md, err := waitForChangeAnalysis(ctx, d.changes, change.GetMetadata().GetUUID(), time.Time{}, timeout)
+	if err != nil {
+		if errors.Is(err, errChangeAnalysisFailed) {
+			resp.Diagnostics.AddError("Change analysis failed",
+				fmt.Sprintf("Overmind could not analyse change %s, so its risks are unknown. "+
+					"Check the change in the Overmind UI for details.", config.ChangeID.ValueString()))
+		} else {
+			resp.Diagnostics.AddError("Failed to read change risks", err.Error())
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(config.setRisks(md)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("ovm.change.analysisStatus", config.AnalysisStatus.ValueString()),
+		attribute.Int64("ovm.change.highRisks", config.HighRiskCount.ValueInt64()),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+
+	status := md.GetChangeAnalysisStatus().GetStatus()
+	gated := !config.FailOnSeverity.IsNull()
+	switch {
+	case status == sdp.ChangeAnalysisStatus_STATUS_DONE:
+	case gated && !config.AllowUnanalysed.ValueBool():
+		span.SetAttributes(attribute.Bool("ovm.change.riskGateFailed", true))
+		resp.Diagnostics.AddError("Change not analysed",
+			fmt.Sprintf("Change %s has no analysis results (%s), so fail_on_severity cannot check its risks. "+
+				"Use overmind_change_analysis to submit a plan for analysis, or set allow_unanalysed to let "+
+				"such changes pass.", config.ChangeID.ValueString(), status))
+		return
+	case status == sdp.ChangeAnalysisStatus_STATUS_UNSPECIFIED:
+		resp.Diagnostics.AddWarning("Change not analysed",
+			fmt.Sprintf("Change %s has not been analysed, so it has no risks yet. Use overmind_change_analysis "+
+				"to submit a plan for analysis.", config.ChangeID.ValueString()))
+	}
+
+	if gated {
+		threshold, _ := parseRiskSeverity(config.FailOnSeverity.ValueString())
+		var titles []string
+		for _, risk := range md.GetRisks() {
+			if risk.GetSeverity() >= threshold {
+				titles = append(titles, fmt.Sprintf("%s: %s", risk.GetSeverity(), risk.GetTitle()))
+			}
+		}
+		if len(titles) > 0 {
+			span.SetAttributes(attribute.Bool("ovm.change.riskGateFailed", true))
+			resp.Diagnostics.AddError("Change is too risky",
+				fmt.Sprintf("Change %s has %d risks at or above %s:\n  - %s",
+					config.ChangeID.ValueString(), len(titles), threshold, strings.Join(titles, "\n  - ")))
+		}
+	}
+}
+
+// findChange loads the change by UUID or by ticket link, whichever is set.
+func (d *changeRisksDataSource) findChange(ctx context.Context, config *changeRisksDataSourceModel) (*sdp.Change, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if config.ChangeID.IsNull() {
+		getResp, err := d.changes.GetChangeByTicketLink(ctx, connect.NewRequest(&sdp.GetChangeByTicketLinkRequest{
+			TicketLink: config.TicketLink.ValueString(),
+		}))
+		if err != nil {
+			if connect.CodeOf(err) == connect.CodeNotFound {
+				diags.AddAttributeError(path.Root("ticket_link"), "Change not found",
+					fmt.Sprintf("No change has the ticket link %q.", config.TicketLink.ValueString()))
+			} else {
+				diags.AddError("Failed to read change", err.Error())
+			}
+			return nil, diags
+		}
+		return getResp.Msg.GetChange(), diags
+	}
+
+	uuidBytes, err := uuidToBytes(config.ChangeID.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("change_id"), "Invalid change ID", err.Error())
+		return nil, diags
+	}
+	getResp, err := d.changes.GetChange(ctx, connect.NewRequest(&sdp.GetChangeRequest{
+		UUID: uuidBytes,
+		Slim: true,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			diags.AddAttributeError(path.Root("change_id"), "Change not found",
+				fmt.Sprintf("No change with ID %s exists.", config.ChangeID.ValueString()))
+		} else {
+			diags.AddError("Failed to read change", err.Error())
+		}
+		return nil, diags
+	}
+	return getResp.Msg.GetChange(), diags
+}
+
+func (m *changeRisksDataSourceModel) setRisks(md *sdp.ChangeRiskMetadata) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.AnalysisStatus = types.StringValue(md.GetChangeAnalysisStatus().GetStatus().String())
+	m.LowRiskCount = types.Int64Value(int64(md.GetNumLowRisk()))
+	m.MediumRiskCount = types.Int64Value(int64(md.GetNumMediumRisk()))
+	m.HighRiskCount = types.Int64Value(int64(md.GetNumHighRisk()))
+
+	m.Risks = make([]riskModel, len(md.GetRisks()))
+	for i, risk := range md.GetRisks() {
+		id := types.StringNull()
+		if len(risk.GetUUID()) > 0 {
+			riskID, err := uuid.FromBytes(risk.GetUUID())
+			if err != nil {
+				diags.AddError("Failed to parse risk UUID", err.Error())
+				return diags
+			}
+			id = types.StringValue(riskID.String())
+		}
+
+		related := make([]relatedItemModel, len(risk.GetRelatedItemRefs()))
+		for j, ref := range risk.GetRelatedItemRefs() {
+			related[j] = relatedItemModel{
+				GloballyUniqueName:   types.StringValue(ref.GloballyUniqueName()),
+				Type:                 types.StringValue(ref.GetType()),
+				Scope:                types.StringValue(ref.GetScope()),
+				UniqueAttributeValue: types.StringValue(ref.GetUniqueAttributeValue()),
+			}
+		}
+
+		m.Risks[i] = riskModel{
+			ID:           id,
+			Title:        types.StringValue(risk.GetTitle()),
+			Severity:     types.StringValue(risk.GetSeverity().String()),
+			Description:  stringOrNull(risk.GetDescription()),
+			RelatedItems: related,
+		}
+	}
+	return diags
+}
+
+// parseRiskSeverity returns the severity with the given name, if it is one of
+// riskSeverities.
+func parseRiskSeverity(name string) (sdp.Risk_Severity, bool) {
+	for _, severity := range riskSeverities {
+		if severity == name {
+			return sdp.Risk_Severity(sdp.Risk_Severity_value[name]), true
+		}
+	}
+	return sdp.Risk_SEVERITY_UNSPECIFIED, false
+}
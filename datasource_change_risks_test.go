@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+)
+
+func (m *mockChangesHandler) GetChangeByTicketLink(_ context.Context, req *connect.Request[sdp.GetChangeByTicketLinkRequest]) (*connect.Response[sdp.GetChangeResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range m.full {
+		if change.GetProperties().GetTicketLink() == req.Msg.GetTicketLink() {
+			return connect.NewResponse(&sdp.GetChangeResponse{Change: change}), nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, nil)
+}
+
+// createRiskyChange creates a change with a ticket link whose analysis is
+// still running and will find one medium and one high risk.
+func createRiskyChange(t *testing.T, mocks *mockServer) string {
+	t.Helper()
+	createResp, err := mocks.changes.CreateChange(context.Background(), connect.NewRequest(&sdp.CreateChangeRequest{
+		Properties: &sdp.ChangeProperties{
+			Title:      "Rotate database credentials",
+			TicketLink: "https://jira.example.com/browse/OPS-7",
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.UUID(createResp.Msg.GetChange().GetMetadata().GetUUID()).String()
+	riskID := uuid.New()
+	mocks.changes.setChangeRisks(id, sdp.ChangeAnalysisStatus_STATUS_INPROGRESS,
+		&sdp.Risk{
+			UUID:        riskID[:],
+			Title:       "Orders API outage",
+			Severity:    sdp.Risk_SEVERITY_HIGH,
+			Description: "The orders API caches credentials for an hour.",
+			RelatedItemRefs: []*sdp.Reference{
+				{Type: "rds-db-instance", UniqueAttributeValue: "orders", Scope: "123456789012.eu-west-2"},
+			},
+		},
+		&sdp.Risk{Title: "Reporting job failure", Severity: sdp.Risk_SEVERITY_MEDIUM},
+	)
+	mocks.changes.mu.Lock()
+	mocks.changes.analysisPolls[id] = 2
+	mocks.changes.mu.Unlock()
+	return id
+}
+
+func TestChangeRisksDataSource(t *testing.T) {
+	shortenChangeAnalysisPolling(t)
+	serverURL, mocks := startMockServer(t)
+	id := createRiskyChange(t, mocks)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `data "overmind_change_risks" "release" {
+  ticket_link      = "https://jira.example.com/browse/OPS-7"
+  fail_on_severity = "SEVERITY_HIGH"
+
+  timeouts {
+    read = "1m"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Change is too risky.*SEVERITY_HIGH: Orders API outage`),
+			},
+			{
+				Config: `data "overmind_change_risks" "release" {
+  change_id = "` + id + `"
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "ticket_link", "https://jira.example.com/browse/OPS-7"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "title", "Rotate database credentials"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "analysis_status", "STATUS_DONE"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "high_risk_count", "1"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "medium_risk_count", "1"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "low_risk_count", "0"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "risks.#", "2"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "risks.0.severity", "SEVERITY_HIGH"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "risks.0.related_items.0.globally_unique_name", "123456789012.eu-west-2.rds-db-instance.orders"),
+					tfresource.TestCheckNoResourceAttr("data.overmind_change_risks.release", "risks.1.description"),
+				),
+			},
+			{
+				// Medium risks pass a high threshold.
+				Config: `data "overmind_change_risks" "release" {
+  change_id        = "` + id + `"
+  fail_on_severity = "SEVERITY_HIGH"
+}`,
+				PreConfig: func() {
+					mocks.changes.setChangeRisks(id, sdp.ChangeAnalysisStatus_STATUS_DONE,
+						&sdp.Risk{Title: "Reporting job failure", Severity: sdp.Risk_SEVERITY_MEDIUM})
+				},
+				Check: tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "risks.#", "1"),
+			},
+			{
+				Config: `data "overmind_change_risks" "release" {
+  ticket_link = "https://jira.example.com/browse/OPS-8"
+}`,
+				ExpectError: regexp.MustCompile(`Change not found`),
+			},
+			{
+				// A change without analysis results does not pass the gate.
+				PreConfig: func() {
+					if _, err := mocks.changes.CreateChange(context.Background(), connect.NewRequest(&sdp.CreateChangeRequest{
+						Properties: &sdp.ChangeProperties{
+							Title:      "Upgrade Redis",
+							TicketLink: "https://jira.example.com/browse/OPS-9",
+						},
+					})); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: `data "overmind_change_risks" "release" {
+  ticket_link      = "https://jira.example.com/browse/OPS-9"
+  fail_on_severity = "SEVERITY_HIGH"
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Change not analysed.*STATUS_UNSPECIFIED`),
+			},
+			{
+				Config: `data "overmind_change_risks" "release" {
+  ticket_link      = "https://jira.example.com/browse/OPS-9"
+  fail_on_severity = "SEVERITY_HIGH"
+  allow_unanalysed = true
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "analysis_status", "STATUS_UNSPECIFIED"),
+					tfresource.TestCheckResourceAttr("data.overmind_change_risks.release", "risks.#", "0"),
+				),
+			},
+			{
+				// Skipped analysis counts as no results too.
+				PreConfig: func() {
+					mocks.changes.setChangeRisks(id, sdp.ChangeAnalysisStatus_STATUS_SKIPPED)
+				},
+				Config: `data "overmind_change_risks" "release" {
+  change_id        = "` + id + `"
+  fail_on_severity = "SEVERITY_LOW"
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Change not analysed.*STATUS_SKIPPED`),
+			},
+			{
+				Config: `data "overmind_change_risks" "release" {
+  change_id        = "` + id + `"
+  fail_on_severity = "high"
+}`,
+				ExpectError: regexp.MustCompile(`Invalid severity`),
+			},
+			{
+				Config: `data "overmind_change_risks" "release" {
+  change_id = "` + id + `"
+
+  timeouts {
+    read = "1 hour"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`Invalid timeout`),
+			},
+		},
+	})
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ datasource.DataSource                   = (*snapshotDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*snapshotDataSource)(nil)
+)
+
+type snapshotDataSource struct {
+	snapshots sdpconnect.SnapshotsServiceClient
+}
+
+type snapshotDataSourceModel struct {
+	ID          types.String        `tfsdk:"id"`
+	Name        types.String        `tfsdk:"name"`
+	Description types.String        `tfsdk:"description"`
+	Created     types.String        `tfsdk:"created"`
+	ItemCount   types.Int64         `tfsdk:"item_count"`
+	EdgeCount   types.Int64         `tfsdk:"edge_count"`
+	Queries     []queryModel        `tfsdk:"queries"`
+	Items       []snapshotItemModel `tfsdk:"items"`
+	Edges       []snapshotEdgeModel `tfsdk:"edges"`
+}
+
+type snapshotItemModel struct {
+	GloballyUniqueName   types.String `tfsdk:"globally_unique_name"`
+	Type                 types.String `tfsdk:"type"`
+	Scope                types.String `tfsdk:"scope"`
+	UniqueAttributeValue types.String `tfsdk:"unique_attribute_value"`
+	Attributes           types.String `tfsdk:"attributes"`
+}
+
+type snapshotEdgeModel struct {
+	From types.String `tfsdk:"from"`
+	To   types.String `tfsdk:"to"`
+}
+
+func NewSnapshotDataSource() datasource.DataSource {
+	return &snapshotDataSource{}
+}
+
+func (d *snapshotDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot"
+}
+
+func (d *snapshotDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Loads an Overmind snapshot by UUID or by name, including every item and edge it captured.",
+		Attributes: map[string]dsschema.Attribute{
+			"id": dsschema.StringAttribute{
+				Description: "UUID of the snapshot. Exactly one of id and name must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"name": dsschema.StringAttribute{
+				Description: "Name of the snapshot. It is an error if no snapshot, or more than one, has this name.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"description": dsschema.StringAttribute{
+				Description: "Description of the snapshot.",
+				Computed:    true,
+			},
+			"created": dsschema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the snapshot was taken.",
+				Computed:    true,
+			},
+			"item_count": dsschema.Int64Attribute{
+				Description: "Number of items in the snapshot.",
+				Computed:    true,
+			},
+			"edge_count": dsschema.Int64Attribute{
+				Description: "Number of edges between items in the snapshot.",
+				Computed:    true,
+			},
+			"queries": queryDataSourceAttribute("Queries whose results were captured."),
+			"items": dsschema.ListNestedAttribute{
+				Description: "Items in the snapshot, in the order the API returns them.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"globally_unique_name": dsschema.StringAttribute{
+							Description: "Scope, type and unique attribute value of the item, joined with dots.",
+							Computed:    true,
+						},
+						"type": dsschema.StringAttribute{
+							Description: "Item type, e.g. \"ec2-instance\".",
+							Computed:    true,
+						},
+						"scope": dsschema.StringAttribute{
+							Description: "Scope the item was found in.",
+							Computed:    true,
+						},
+						"unique_attribute_value": dsschema.StringAttribute{
+							Description: "Value of the item's unique attribute.",
+							Computed:    true,
+						},
+						"attributes": dsschema.StringAttribute{
+							Description: "The item's attributes as a JSON object. Use jsondecode to read them.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"edges": dsschema.ListNestedAttribute{
+				Description: "Edges between items, by globally unique name.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"from": dsschema.StringAttribute{
+							Description: "Globally unique name of the item the edge starts at.",
+							Computed:    true,
+						},
+						"to": dsschema.StringAttribute{
+							Description: "Globally unique name of the item the edge ends at.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *snapshotDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config snapshotDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ID.IsUnknown() || config.Name.IsUnknown() {
+		return
+	}
+
+	if config.ID.IsNull() == config.Name.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Invalid snapshot lookup",
+			"Exactly one of id and name must be set.")
+		return
+	}
+
+	if !config.ID.IsNull() {
+		if _, err := uuidToBytes(config.ID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("id"), "Invalid snapshot ID", err.Error())
+		}
+	}
+}
+
+func (d *snapshotDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.snapshots = clients.Snapshots
+}
+
+func (d *snapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot DataSource Read")
+	defer span.End()
+
+	var config snapshotDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var uuidBytes []byte
+	if config.ID.IsNull() {
+		span.SetAttributes(attribute.String("ovm.snapshot.name", config.Name.ValueString()))
+		var diags diag.Diagnostics
+		uuidBytes, diags = d.findSnapshotByName(ctx, config.Name.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			span.SetStatus(codes.Error, "snapshot lookup by name failed")
+			return
+		}
+	} else {
+		span.SetAttributes(attribute.String("ovm.snapshot.id", config.ID.ValueString()))
+		var err error
+		uuidBytes, err = uuidToBytes(config.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid snapshot ID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "invalid UUID")
+			return
+		}
+	}
+
+	getResp, err := d.snapshots.GetSnapshot(ctx, connect.NewRequest(&sdp.GetSnapshotRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSnapshot failed")
+		return
+	}
+
+	resp.Diagnostics.Append(config.setSnapshot(getResp.Msg.GetSnapshot())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.Int64("ovm.snapshot.items", config.ItemCount.ValueInt64()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// findSnapshotByName returns the UUID of the only snapshot with the given
+// name.
+func (d *snapshotDataSource) findSnapshotByName(ctx context.Context, name string) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	listResp, err := d.snapshots.ListSnapshots(ctx, connect.NewRequest(&sdp.ListSnapshotsRequest{}))
+	if err != nil {
+		diags.AddError("Failed to list snapshots", err.Error())
+		return nil, diags
+	}
+
+	var matches [][]byte
+	for _, snapshot := range listResp.Msg.GetSnapshots() {
+		if snapshot.GetProperties().GetName() == name {
+			matches = append(matches, snapshot.GetMetadata().GetUUID())
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		diags.AddAttributeError(path.Root("name"), "Snapshot not found",
+			fmt.Sprintf("No snapshot is named %q.", name))
+	case 1:
+		return matches[0], diags
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = uuid.UUID(m).String()
+		}
+		diags.AddAttributeError(path.Root("name"), "Ambiguous snapshot name",
+			fmt.Sprintf("%d snapshots are named %q (%s). Look the snapshot up by id instead.", len(matches), name, strings.Join(ids, ", ")))
+	}
+	return nil, diags
+}
+
+func (m *snapshotDataSourceModel) setSnapshot(snapshot *sdp.Snapshot) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	id, err := uuid.FromBytes(snapshot.GetMetadata().GetUUID())
+	if err != nil {
+		diags.AddError("Failed to parse snapshot UUID", err.Error())
+		return diags
+	}
+
+	props := snapshot.GetProperties()
+	m.ID = types.StringValue(id.String())
+	m.Name = types.StringValue(props.GetName())
+	m.Description = stringOrNull(props.GetDescription())
+	m.Created = timestampString(snapshot.GetMetadata().GetCreated())
+	m.ItemCount = types.Int64Value(int64(len(props.GetItems())))
+	m.EdgeCount = types.Int64Value(int64(len(props.GetEdges())))
+	m.Queries = queryModelsFromSDP(props.GetQueries())
+
+	m.Items = make([]snapshotItemModel, len(props.GetItems()))
+	for i, item := range props.GetItems() {
+		attrs, err := json.Marshal(item.GetAttributes().GetAttrStruct().AsMap())
+		if err != nil {
+			diags.AddError("Failed to encode item attributes",
+				fmt.Sprintf("Could not encode the attributes of %s as JSON: %s", item.GloballyUniqueName(), err))
+			return diags
+		}
+		m.Items[i] = snapshotItemModel{
+			GloballyUniqueName:   types.StringValue(item.GloballyUniqueName()),
+			Type:                 types.StringValue(item.GetType()),
+			Scope:                types.StringValue(item.GetScope()),
+			UniqueAttributeValue: types.StringValue(item.UniqueAttributeValue()),
+			Attributes:           types.StringValue(string(attrs)),
+		}
+	}
+
+	m.Edges = make([]snapshotEdgeModel, len(props.GetEdges()))
+	for i, edge := range props.GetEdges() {
+		m.Edges[i] = snapshotEdgeModel{
+			From: types.StringValue(edge.GetFrom().GloballyUniqueName()),
+			To:   types.StringValue(edge.GetTo().GloballyUniqueName()),
+		}
+	}
+	return diags
+}
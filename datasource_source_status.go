@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var _ datasource.DataSource = (*sourceStatusDataSource)(nil)
+
+type sourceStatusDataSource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type sourceStatusDataSourceModel struct {
+	ID         types.String        `tfsdk:"id"`
+	AllHealthy types.Bool          `tfsdk:"all_healthy"`
+	Sources    []sourceStatusModel `tfsdk:"sources"`
+}
+
+type sourceStatusModel struct {
+	ID              types.String           `tfsdk:"id"`
+	Name            types.String           `tfsdk:"name"`
+	Type            types.String           `tfsdk:"type"`
+	Status          types.String           `tfsdk:"status"`
+	Healthy         types.Bool             `tfsdk:"healthy"`
+	Error           types.String           `tfsdk:"error"`
+	Version         types.String           `tfsdk:"version"`
+	Managed         types.Bool             `tfsdk:"managed"`
+	CreatedAt       types.String           `tfsdk:"created_at"`
+	LastHeartbeat   types.String           `tfsdk:"last_heartbeat"`
+	NextHeartbeat   types.String           `tfsdk:"next_heartbeat"`
+	AvailableTypes  []string               `tfsdk:"available_types"`
+	AvailableScopes []string               `tfsdk:"available_scopes"`
+	Adapters        []adapterMetadataModel `tfsdk:"adapters"`
+}
+
+type adapterMetadataModel struct {
+	Type            types.String `tfsdk:"type"`
+	Category        types.String `tfsdk:"category"`
+	DescriptiveName types.String `tfsdk:"descriptive_name"`
+	PotentialLinks  []string     `tfsdk:"potential_links"`
+	SupportsGet     types.Bool   `tfsdk:"supports_get"`
+	SupportsList    types.Bool   `tfsdk:"supports_list"`
+	SupportsSearch  types.Bool   `tfsdk:"supports_search"`
+}
+
+func NewSourceStatusDataSource() datasource.DataSource {
+	return &sourceStatusDataSource{}
+}
+
+func (d *sourceStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_source_status"
+}
+
+func (d *sourceStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Reports the health of Overmind sources. Without an id, returns every source in the account. " +
+			"Use it in check blocks to assert that sources are healthy and to discover the scopes they cover.",
+		Attributes: map[string]dsschema.Attribute{
+			"id": dsschema.StringAttribute{
+				Description: "UUID of a single source to report on. If unset, all sources are returned.",
+				Optional:    true,
+			},
+			"all_healthy": dsschema.BoolAttribute{
+				Description: "True when every returned source has status STATUS_HEALTHY.",
+				Computed:    true,
+			},
+			"sources": dsschema.ListNestedAttribute{
+				Description: "Health of each source.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "Source UUID.",
+							Computed:    true,
+						},
+						"name": dsschema.StringAttribute{
+							Description: "Source name.",
+							Computed:    true,
+						},
+						"type": dsschema.StringAttribute{
+							Description: "Source type, e.g. aws or gcp.",
+							Computed:    true,
+						},
+						"status": dsschema.StringAttribute{
+							Description: "Source status, e.g. STATUS_HEALTHY, STATUS_UNHEALTHY or STATUS_PROGRESSING.",
+							Computed:    true,
+						},
+						"healthy": dsschema.BoolAttribute{
+							Description: "True when status is STATUS_HEALTHY.",
+							Computed:    true,
+						},
+						"error": dsschema.StringAttribute{
+							Description: "Error reported by the source, if it is unhealthy.",
+							Computed:    true,
+						},
+						"version": dsschema.StringAttribute{
+							Description: "Version of the running source.",
+							Computed:    true,
+						},
+						"managed": dsschema.BoolAttribute{
+							Description: "Whether the source is run by Overmind rather than self-hosted.",
+							Computed:    true,
+						},
+						"created_at": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of when the source was created.",
+							Computed:    true,
+						},
+						"last_heartbeat": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of the last heartbeat received from the source.",
+							Computed:    true,
+						},
+						"next_heartbeat": dsschema.StringAttribute{
+							Description: "RFC 3339 timestamp of the next heartbeat expected from the source.",
+							Computed:    true,
+						},
+						"available_types": dsschema.ListAttribute{
+							Description: "Item types the source can discover.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"available_scopes": dsschema.ListAttribute{
+							Description: "Scopes the source can discover, e.g. AWS account/region pairs.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"adapters": dsschema.ListNestedAttribute{
+							Description: "Adapters reported by the source, one per item type.",
+							Computed:    true,
+							NestedObject: dsschema.NestedAttributeObject{
+								Attributes: map[string]dsschema.Attribute{
+									"type": dsschema.StringAttribute{
+										Description: "Item type returned by the adapter, e.g. ec2-instance.",
+										Computed:    true,
+									},
+									"category": dsschema.StringAttribute{
+										Description: "Adapter category, e.g. ADAPTER_CATEGORY_NETWORK.",
+										Computed:    true,
+									},
+									"descriptive_name": dsschema.StringAttribute{
+										Description: "Human-readable name of the item type.",
+										Computed:    true,
+									},
+									"potential_links": dsschema.ListAttribute{
+										Description: "Item types that items of this type can link to.",
+										Computed:    true,
+										ElementType: types.StringType,
+									},
+									"supports_get": dsschema.BoolAttribute{
+										Description: "Whether the adapter supports GET queries.",
+										Computed:    true,
+									},
+									"supports_list": dsschema.BoolAttribute{
+										Description: "Whether the adapter supports LIST queries.",
+										Computed:    true,
+									},
+									"supports_search": dsschema.BoolAttribute{
+										Description: "Whether the adapter supports SEARCH queries.",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *sourceStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.mgmt = clients.Management
+}
+
+func (d *sourceStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "SourceStatus Read")
+	defer span.End()
+
+	var config sourceStatusDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var healths []*sdp.SourceHealth
+	if !config.ID.IsNull() {
+		span.SetAttributes(attribute.String("ovm.source.id", config.ID.ValueString()))
+
+		uuidBytes, err := uuidToBytes(config.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid source ID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "invalid UUID")
+			return
+		}
+
+		statusResp, err := d.mgmt.GetSourceStatus(ctx, connect.NewRequest(&sdp.GetSourceStatusRequest{
+			SourceUuid: uuidBytes,
+		}))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get source status", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetSourceStatus failed")
+			return
+		}
+		healths = []*sdp.SourceHealth{statusResp.Msg.GetSource()}
+	} else {
+		listResp, err := d.mgmt.ListAllSourcesStatus(ctx, connect.NewRequest(&sdp.ListAllSourcesStatusRequest{}))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to list source status", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "ListAllSourcesStatus failed")
+			return
+		}
+		healths = listResp.Msg.GetSources()
+	}
+
+	config.AllHealthy = types.BoolValue(true)
+	config.Sources = make([]sourceStatusModel, 0, len(healths))
+	for _, health := range healths {
+		status := sourceStatusModelFromHealth(health)
+		if !status.Healthy.ValueBool() {
+			config.AllHealthy = types.BoolValue(false)
+		}
+		config.Sources = append(config.Sources, status)
+	}
+
+	span.SetAttributes(
+		attribute.Int("ovm.sources.count", len(config.Sources)),
+		attribute.Bool("ovm.sources.allHealthy", config.AllHealthy.ValueBool()),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+func sourceStatusModelFromHealth(health *sdp.SourceHealth) sourceStatusModel {
+	id := ""
+	if parsed, err := uuid.FromBytes(health.GetUUID()); err == nil {
+		id = parsed.String()
+	}
+
+	adapters := make([]adapterMetadataModel, 0, len(health.GetAdapterMetadata()))
+	for _, md := range health.GetAdapterMetadata() {
+		methods := md.GetSupportedQueryMethods()
+		adapters = append(adapters, adapterMetadataModel{
+			Type:            types.StringValue(md.GetType()),
+			Category:        types.StringValue(md.GetCategory().String()),
+			DescriptiveName: types.StringValue(md.GetDescriptiveName()),
+			PotentialLinks:  nonNilStrings(md.GetPotentialLinks()),
+			SupportsGet:     types.BoolValue(methods.GetGet()),
+			SupportsList:    types.BoolValue(methods.GetList()),
+			SupportsSearch:  types.BoolValue(methods.GetSearch()),
+		})
+	}
+
+	return sourceStatusModel{
+		ID:              types.StringValue(id),
+		Name:            types.StringValue(health.GetName()),
+		Type:            types.StringValue(health.GetType()),
+		Status:          types.StringValue(health.GetStatus().String()),
+		Healthy:         types.BoolValue(health.GetStatus() == sdp.SourceStatus_STATUS_HEALTHY),
+		Error:           stringOrNull(health.GetError()),
+		Version:         stringOrNull(health.GetVersion()),
+		Managed:         types.BoolValue(health.GetManaged() == sdp.SourceManaged_MANAGED),
+		CreatedAt:       timestampString(health.GetCreatedAt()),
+		LastHeartbeat:   timestampString(health.GetLastHeartbeat()),
+		NextHeartbeat:   timestampString(health.GetNextHeartbeat()),
+		AvailableTypes:  nonNilStrings(health.GetAvailableTypes()),
+		AvailableScopes: nonNilStrings(health.GetAvailableScopes()),
+		Adapters:        adapters,
+	}
+}
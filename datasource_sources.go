@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	_ datasource.DataSource                   = (*sourcesDataSource)(nil)
+	_ datasource.DataSourceWithValidateConfig = (*sourcesDataSource)(nil)
+)
+
+type sourcesDataSource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type sourcesDataSourceModel struct {
+	Type      types.String         `tfsdk:"type"`
+	NameRegex types.String         `tfsdk:"name_regex"`
+	Status    types.String         `tfsdk:"status"`
+	IDs       []string             `tfsdk:"ids"`
+	Sources   []sourceSummaryModel `tfsdk:"sources"`
+}
+
+type sourceSummaryModel struct {
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Type                 types.String `tfsdk:"type"`
+	ConfigJSON           types.String `tfsdk:"config_json"`
+	AdditionalConfigJSON types.String `tfsdk:"additional_config_json"`
+	Status               types.String `tfsdk:"status"`
+	Error                types.String `tfsdk:"error"`
+}
+
+func NewSourcesDataSource() datasource.DataSource {
+	return &sourcesDataSource{}
+}
+
+func (d *sourcesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sources"
+}
+
+func (d *sourcesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Lists the sources in the current Overmind account, optionally filtered by type, name and status.",
+		Attributes: map[string]dsschema.Attribute{
+			"type": dsschema.StringAttribute{
+				Description: "Only return sources of this type, e.g. \"aws\" or \"gcp\".",
+				Optional:    true,
+			},
+			"name_regex": dsschema.StringAttribute{
+				Description: "Only return sources whose name matches this regular expression (RE2 syntax).",
+				Optional:    true,
+			},
+			"status": dsschema.StringAttribute{
+				Description: "Only return sources with this status, e.g. \"STATUS_HEALTHY\" or \"STATUS_UNHEALTHY\".",
+				Optional:    true,
+			},
+			"ids": dsschema.ListAttribute{
+				Description: "UUIDs of the matching sources, in the same order as sources.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"sources": dsschema.ListNestedAttribute{
+				Description: "Matching sources, ordered by name.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "Source UUID.",
+							Computed:    true,
+						},
+						"name": dsschema.StringAttribute{
+							Description: "Source name.",
+							Computed:    true,
+						},
+						"type": dsschema.StringAttribute{
+							Description: "Source type.",
+							Computed:    true,
+						},
+						"config_json": dsschema.StringAttribute{
+							Description: "Source configuration as a JSON object, without credentials. " +
+								"Use jsondecode() to access it.",
+							Computed: true,
+						},
+						"additional_config_json": dsschema.StringAttribute{
+							Description: "Additional source configuration as a JSON object, or null if unset.",
+							Computed:    true,
+						},
+						"status": dsschema.StringAttribute{
+							Description: "Source status, or STATUS_UNSPECIFIED if the source has never reported in.",
+							Computed:    true,
+						},
+						"error": dsschema.StringAttribute{
+							Description: "Error reported by the source, if it is unhealthy.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *sourcesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config sourcesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.NameRegex.IsNull() && !config.NameRegex.IsUnknown() {
+		if _, err := regexp.Compile(config.NameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+		}
+	}
+
+	if !config.Status.IsNull() && !config.Status.IsUnknown() {
+		if _, ok := sdp.SourceStatus_value[config.Status.ValueString()]; !ok {
+			valid := make([]string, 0, len(sdp.SourceStatus_value))
+			for name := range sdp.SourceStatus_value {
+				valid = append(valid, name)
+			}
+			slices.Sort(valid)
+			resp.Diagnostics.AddAttributeError(path.Root("status"), "Invalid source status",
+				fmt.Sprintf("status must be one of %s, got %q.", strings.Join(valid, ", "), config.Status.ValueString()))
+		}
+	}
+}
+
+func (d *sourcesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.mgmt = clients.Management
+}
+
+func (d *sourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Sources Read")
+	defer span.End()
+
+	var config sourcesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	listResp, err := d.mgmt.ListSources(ctx, connect.NewRequest(&sdp.ListSourcesRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list sources", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListSources failed")
+		return
+	}
+
+	// Fetch the status of every source with a single call rather than one
+	// GetSourceStatus per source, so large accounts stay cheap to read.
+	statusResp, err := d.mgmt.ListAllSourcesStatus(ctx, connect.NewRequest(&sdp.ListAllSourcesStatusRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list source status", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListAllSourcesStatus failed")
+		return
+	}
+	healthByID := make(map[uuid.UUID]*sdp.SourceHealth, len(statusResp.Msg.GetSources()))
+	for _, health := range statusResp.Msg.GetSources() {
+		if id, err := uuid.FromBytes(health.GetUUID()); err == nil {
+			healthByID[id] = health
+		}
+	}
+
+	config.Sources = []sourceSummaryModel{}
+	for _, source := range listResp.Msg.GetSources() {
+		props := source.GetProperties()
+		if !config.Type.IsNull() && props.GetType() != config.Type.ValueString() {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(props.GetDescriptiveName()) {
+			continue
+		}
+
+		id, err := uuid.FromBytes(source.GetMetadata().GetUUID())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse source UUID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "UUID parse failed")
+			return
+		}
+
+		health := healthByID[id]
+		status := health.GetStatus().String()
+		if !config.Status.IsNull() && status != config.Status.ValueString() {
+			continue
+		}
+
+		configJSON, err := sourceConfigJSON(props.GetConfig())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encode source config", err.Error())
+			return
+		}
+		additionalConfigJSON, err := sourceConfigJSON(props.GetAdditionalConfig())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encode source additional config", err.Error())
+			return
+		}
+
+		config.Sources = append(config.Sources, sourceSummaryModel{
+			ID:                   types.StringValue(id.String()),
+			Name:                 types.StringValue(props.GetDescriptiveName()),
+			Type:                 types.StringValue(props.GetType()),
+			ConfigJSON:           configJSON,
+			AdditionalConfigJSON: additionalConfigJSON,
+			Status:               types.StringValue(status),
+			Error:                stringOrNull(health.GetError()),
+		})
+	}
+
+	slices.SortFunc(config.Sources, func(a, b sourceSummaryModel) int {
+		if c := strings.Compare(a.Name.ValueString(), b.Name.ValueString()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.ValueString(), b.ID.ValueString())
+	})
+
+	config.IDs = make([]string, len(config.Sources))
+	for i, source := range config.Sources {
+		config.IDs[i] = source.ID.ValueString()
+	}
+
+	span.SetAttributes(
+		attribute.Int("ovm.sources.total", len(listResp.Msg.GetSources())),
+		attribute.Int("ovm.sources.matched", len(config.Sources)),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// sensitiveSourceConfigKeys are config keys holding credentials. They are
+// left out of config_json, and of overmind_source config when reading or
+// listing sources, so that secrets are never copied from the API into state.
+var sensitiveSourceConfigKeys = []string{ //nolint:gochecknoglobals // constant list
+	"aws-secret-access-key",
+}
+
+// sourceConfigJSON encodes a source config as compact JSON with sorted keys
+// so that the value is stable between reads. A nil Struct encodes to null.
+func sourceConfigJSON(s *structpb.Struct) (types.String, error) {
+	if s == nil {
+		return types.StringNull(), nil
+	}
+	fields := s.AsMap()
+	for _, k := range sensitiveSourceConfigKeys {
+		delete(fields, k)
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	return types.StringValue(string(b)), nil
+}
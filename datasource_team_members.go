@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var _ datasource.DataSource = (*teamMembersDataSource)(nil)
+
+type teamMembersDataSource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type teamMembersDataSourceModel struct {
+	IDs     []string          `tfsdk:"ids"`
+	Names   []string          `tfsdk:"names"`
+	Members []teamMemberModel `tfsdk:"members"`
+}
+
+type teamMemberModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	PictureURL types.String `tfsdk:"picture_url"`
+}
+
+func NewTeamMembersDataSource() datasource.DataSource {
+	return &teamMembersDataSource{}
+}
+
+func (d *teamMembersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team_members"
+}
+
+func (d *teamMembersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dsschema.Schema{
+		Description: "Lists the members of the current Overmind account. Users who have been invited but " +
+			"have not yet accepted are not members; see overmind_team_invite.",
+		Attributes: map[string]dsschema.Attribute{
+			"ids": dsschema.ListAttribute{
+				Description: "UUIDs of the members, in the same order as members.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"names": dsschema.ListAttribute{
+				Description: "Display names of the members, in the same order as members.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"members": dsschema.ListNestedAttribute{
+				Description: "Team members, ordered by name.",
+				Computed:    true,
+				NestedObject: dsschema.NestedAttributeObject{
+					Attributes: map[string]dsschema.Attribute{
+						"id": dsschema.StringAttribute{
+							Description: "Team member UUID.",
+							Computed:    true,
+						},
+						"name": dsschema.StringAttribute{
+							Description: "Display name.",
+							Computed:    true,
+						},
+						"picture_url": dsschema.StringAttribute{
+							Description: "URL of the member's profile picture, if any.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *teamMembersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected DataSource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	d.mgmt = clients.Management
+}
+
+func (d *teamMembersDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamMembers Read")
+	defer span.End()
+
+	listResp, err := d.mgmt.ListTeamMembers(ctx, connect.NewRequest(&sdp.ListTeamMembersRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list team members", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListTeamMembers failed")
+		return
+	}
+
+	state := teamMembersDataSourceModel{
+		Members: make([]teamMemberModel, 0, len(listResp.Msg.GetMembers())),
+	}
+	for _, member := range listResp.Msg.GetMembers() {
+		id, err := uuid.FromBytes(member.GetUUID())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to parse team member UUID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "UUID parse failed")
+			return
+		}
+		state.Members = append(state.Members, teamMemberModel{
+			ID:         types.StringValue(id.String()),
+			Name:       types.StringValue(member.GetName()),
+			PictureURL: stringOrNull(member.GetPictureUrl()),
+		})
+	}
+
+	slices.SortFunc(state.Members, func(a, b teamMemberModel) int {
+		if c := strings.Compare(a.Name.ValueString(), b.Name.ValueString()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.ValueString(), b.ID.ValueString())
+	})
+
+	state.IDs = make([]string, len(state.Members))
+	state.Names = make([]string, len(state.Members))
+	for i, member := range state.Members {
+		state.IDs[i] = member.ID.ValueString()
+		state.Names[i] = member.Name.ValueString()
+	}
+
+	span.SetAttributes(attribute.Int("ovm.teamMembers.count", len(state.Members)))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
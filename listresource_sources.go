@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	_ list.ListResourceWithConfigure      = (*awsSourceListResource)(nil)
+	_ list.ListResourceWithValidateConfig = (*awsSourceListResource)(nil)
+	_ list.ListResourceWithConfigure      = (*gcpSourceListResource)(nil)
+	_ list.ListResourceWithValidateConfig = (*gcpSourceListResource)(nil)
+	_ list.ListResourceWithConfigure      = (*sourceListResource)(nil)
+	_ list.ListResourceWithValidateConfig = (*sourceListResource)(nil)
+)
+
+// sourceListBase holds what every source list resource has in common: the
+// management client and a name_regex filter. Each source resource embeds it
+// and only supplies Metadata and List.
+type sourceListBase struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+func (l *sourceListBase) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected ListResource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	l.mgmt = clients.Management
+}
+
+func (l *sourceListBase) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		Attributes: map[string]listschema.Attribute{
+			"name_regex": listschema.StringAttribute{
+				Description: "Only return sources whose name matches this regular expression (RE2 syntax).",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (l *sourceListBase) ValidateListResourceConfig(ctx context.Context, req list.ValidateConfigRequest, resp *list.ValidateConfigResponse) {
+	var nameRegex types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name_regex"), &nameRegex)...)
+	if resp.Diagnostics.HasError() || nameRegex.IsNull() || nameRegex.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(nameRegex.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+	}
+}
+
+// awsSourceListResource lists overmind_aws_source instances for
+// `terraform query`.
+type awsSourceListResource struct {
+	sourceListBase
+}
+
+func NewAWSSourceListResource() list.ListResource {
+	return &awsSourceListResource{}
+}
+
+func (l *awsSourceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_source"
+}
+
+func (l *awsSourceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	ctx, span := tracing.Tracer().Start(ctx, "AWSSource List")
+	defer span.End()
+
+	// The external ID is per account, so fetch it once rather than per
+	// source, and only when Terraform wants the full resource.
+	var externalID string
+	if req.IncludeResource {
+		extIDResp, err := l.mgmt.GetOrCreateAWSExternalId(ctx,
+			connect.NewRequest(&sdp.GetOrCreateAWSExternalIdRequest{}))
+		if err != nil {
+			var diags diag.Diagnostics
+			diags.AddError("Failed to get AWS external ID", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetOrCreateAWSExternalId failed")
+			return
+		}
+		externalID = extIDResp.Msg.GetAwsExternalId()
+	}
+
+	listSourcesOfType(ctx, l.mgmt, awsSourceType, req, stream, func(id types.String, props *sdp.SourceProperties) (any, diag.Diagnostics) {
+		model := awsSourceResourceModel{
+			ID:             id,
+			AccessStrategy: types.StringNull(),
+			AWSRoleARN:     types.StringNull(),
+			AWSRegions:     types.ListNull(types.StringType),
+			ExternalID:     types.StringValue(externalID),
+			WaitForHealthy: types.BoolValue(true),
+			Health:         types.ObjectNull(sourceHealthAttrTypes),
+		}
+		diags := model.setSourceProperties(ctx, props)
+		return &model, diags
+	})
+}
+
+// gcpSourceListResource lists overmind_gcp_source instances for
+// `terraform query`.
+type gcpSourceListResource struct {
+	sourceListBase
+}
+
+func NewGCPSourceListResource() list.ListResource {
+	return &gcpSourceListResource{}
+}
+
+func (l *gcpSourceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcp_source"
+}
+
+func (l *gcpSourceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource List")
+	defer span.End()
+
+	var saName string
+	if req.IncludeResource {
+		var err error
+		saName, err = (&gcpSourceResource{mgmt: l.mgmt}).overmindServiceAccountName(ctx)
+		if err != nil {
+			var diags diag.Diagnostics
+			diags.AddError("Failed to get Overmind account", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetAccount failed")
+			return
+		}
+	}
+
+	listSourcesOfType(ctx, l.mgmt, gcpSourceType, req, stream, func(id types.String, props *sdp.SourceProperties) (any, diag.Diagnostics) {
+		model := gcpSourceResourceModel{
+			ID:                               id,
+			GCPProjectIDs:                    types.ListNull(types.StringType),
+			GCPRegions:                       types.ListNull(types.StringType),
+			ImpersonationServiceAccountEmail: types.StringNull(),
+			OvermindServiceAccountName:       types.StringValue(saName),
+			Health:                           types.ObjectNull(sourceHealthAttrTypes),
+		}
+		diags := model.setSourceProperties(ctx, props)
+		return &model, diags
+	})
+}
+
+// sourceListResource lists overmind_source instances for `terraform query`.
+// As overmind_source can manage any type, it lists every source unless
+// filtered by type.
+type sourceListResource struct {
+	sourceListBase
+}
+
+func NewSourceListResource() list.ListResource {
+	return &sourceListResource{}
+}
+
+func (l *sourceListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_source"
+}
+
+func (l *sourceListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	l.sourceListBase.ListResourceConfigSchema(ctx, req, resp)
+	resp.Schema.Attributes["type"] = listschema.StringAttribute{
+		Description: "Only return sources of this type, e.g. \"aws\" or \"gcp\". If unset, sources of every type are returned.",
+		Optional:    true,
+	}
+}
+
+func (l *sourceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source List")
+	defer span.End()
+
+	var sourceType types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("type"), &sourceType)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	listSourcesOfType(ctx, l.mgmt, sourceType.ValueString(), req, stream, func(id types.String, props *sdp.SourceProperties) (any, diag.Diagnostics) {
+		model := sourceResourceModel{
+			ID:     id,
+			Health: types.ObjectNull(sourceHealthAttrTypes),
+		}
+		diags := model.setSourceProperties(ctx, props)
+		return &model, diags
+	})
+}
+
+// listSourcesOfType streams the sources of sourceType (all sources if empty)
+// that match the list config's name_regex, ordered by name. toResource builds
+// the resource model for a source and is only called when Terraform asks for
+// full resource objects, e.g. to generate configuration.
+//
+// All API calls are made before returning, as Terraform consumes the results
+// after List has ended its span.
+func listSourcesOfType(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceType string, req list.ListRequest, stream *list.ListResultsStream, toResource func(types.String, *sdp.SourceProperties) (any, diag.Diagnostics)) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("ovm.source.type", sourceType),
+		attribute.Bool("ovm.list.includeResource", req.IncludeResource),
+		attribute.Int64("ovm.list.limit", req.Limit),
+	)
+
+	var nameRegexValue types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("name_regex"), &nameRegexValue)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !nameRegexValue.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(nameRegexValue.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+	}
+
+	listResp, err := mgmt.ListSources(ctx, connect.NewRequest(&sdp.ListSourcesRequest{}))
+	if err != nil {
+		diags.AddError("Failed to list sources", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListSources failed")
+		return
+	}
+
+	sources := slices.DeleteFunc(slices.Clone(listResp.Msg.GetSources()), func(source *sdp.Source) bool {
+		props := source.GetProperties()
+		if sourceType != "" && props.GetType() != sourceType {
+			return true
+		}
+		return nameRegex != nil && !nameRegex.MatchString(props.GetDescriptiveName())
+	})
+	slices.SortFunc(sources, func(a, b *sdp.Source) int {
+		if c := strings.Compare(a.GetProperties().GetDescriptiveName(), b.GetProperties().GetDescriptiveName()); c != 0 {
+			return c
+		}
+		return slices.Compare(a.GetMetadata().GetUUID(), b.GetMetadata().GetUUID())
+	})
+	if req.Limit > 0 && int64(len(sources)) > req.Limit {
+		sources = sources[:req.Limit]
+	}
+
+	results := make([]list.ListResult, 0, len(sources))
+	for _, source := range sources {
+		props := source.GetProperties()
+		result := req.NewListResult(ctx)
+		result.DisplayName = props.GetDescriptiveName()
+
+		sourceUUID, err := uuid.FromBytes(source.GetMetadata().GetUUID())
+		if err != nil {
+			result.Diagnostics.AddError("Failed to parse source UUID", err.Error())
+			results = append(results, result)
+			continue
+		}
+		id := types.StringValue(sourceUUID.String())
+
+		result.Diagnostics.Append(result.Identity.Set(ctx, sourceIdentityModel{ID: id})...)
+		if req.IncludeResource {
+			model, diags := toResource(id, props)
+			result.Diagnostics.Append(diags...)
+			if !diags.HasError() {
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+		}
+		results = append(results, result)
+	}
+
+	span.SetAttributes(
+		attribute.Int("ovm.sources.total", len(listResp.Msg.GetSources())),
+		attribute.Int("ovm.sources.matched", len(results)),
+	)
+
+	stream.Results = slices.Values(results)
+}
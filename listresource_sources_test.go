@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// runSourceList calls List on a source list resource directly, as Terraform
+// would for `terraform query`, and collects the streamed results.
+func runSourceList(t *testing.T, serverURL string, l list.ListResource, r resource.ResourceWithIdentity, config map[string]tftypes.Value, includeResource bool, limit int64) []list.ListResult {
+	t.Helper()
+	ctx := context.Background()
+
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"}))
+	var configureResp resource.ConfigureResponse
+	l.(list.ListResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{
+		ProviderData: testClients(httpClient, serverURL),
+	}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	var identityResp resource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identityResp)
+	var listSchemaResp list.ListResourceSchemaResponse
+	l.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &listSchemaResp)
+
+	for name := range listSchemaResp.Schema.Attributes {
+		if _, ok := config[name]; !ok {
+			config[name] = tftypes.NewValue(tftypes.String, nil)
+		}
+	}
+
+	var stream list.ListResultsStream
+	l.List(ctx, list.ListRequest{
+		Config: tfsdk.Config{
+			Schema: listSchemaResp.Schema,
+			Raw:    tftypes.NewValue(listSchemaResp.Schema.Type().TerraformType(ctx), config),
+		},
+		IncludeResource:        includeResource,
+		Limit:                  limit,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identityResp.IdentitySchema,
+	}, &stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("List: %v", result.Diagnostics)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestAWSSourceListResource_List(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+
+	prodID := addMockSource(handler, "prod-aws", "aws", map[string]any{
+		"aws-access-strategy": "external-id",
+		"aws-target-role-arn": "arn:aws:iam::123456789012:role/prod",
+		"aws-regions":         []any{"us-east-1", "eu-west-1"},
+		"aws-external-id":     "test-external-id-12345",
+	})
+	addMockSource(handler, "dev-aws", "aws", map[string]any{
+		"aws-access-strategy": "sso-profile",
+		"aws-regions":         []any{"us-west-2"},
+		"aws-profile":         "dev",
+	})
+	addMockSource(handler, "prod-gcp", "gcp", map[string]any{
+		"gcp-project-ids": []any{"project-a"},
+	})
+
+	// Identities only, in name order, GCP source excluded.
+	results := runSourceList(t, serverURL, NewAWSSourceListResource(), &awsSourceResource{}, map[string]tftypes.Value{}, false, 0)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].DisplayName != "dev-aws" || results[1].DisplayName != "prod-aws" {
+		t.Errorf("unexpected order: %q, %q", results[0].DisplayName, results[1].DisplayName)
+	}
+
+	// Full resources, filtered by name, with enough attributes to generate
+	// configuration.
+	results = runSourceList(t, serverURL, NewAWSSourceListResource(), &awsSourceResource{}, map[string]tftypes.Value{
+		"name_regex": tftypes.NewValue(tftypes.String, "^prod-"),
+	}, true, 0)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	var identity sourceIdentityModel
+	if diags := results[0].Identity.Get(context.Background(), &identity); diags.HasError() {
+		t.Fatalf("identity: %v", diags)
+	}
+	if identity.ID.ValueString() != prodID {
+		t.Errorf("identity id = %q, want %q", identity.ID.ValueString(), prodID)
+	}
+
+	var model awsSourceResourceModel
+	if diags := results[0].Resource.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("resource: %v", diags)
+	}
+	if model.Name.ValueString() != "prod-aws" {
+		t.Errorf("name = %q", model.Name.ValueString())
+	}
+	if model.AccessStrategy.ValueString() != "external-id" {
+		t.Errorf("access_strategy = %q", model.AccessStrategy.ValueString())
+	}
+	if model.AWSRoleARN.ValueString() != "arn:aws:iam::123456789012:role/prod" {
+		t.Errorf("aws_role_arn = %q", model.AWSRoleARN.ValueString())
+	}
+	if len(model.AWSRegions.Elements()) != 2 {
+		t.Errorf("aws_regions = %v", model.AWSRegions)
+	}
+
+	// Limit caps the number of results.
+	results = runSourceList(t, serverURL, NewAWSSourceListResource(), &awsSourceResource{}, map[string]tftypes.Value{}, false, 1)
+	if len(results) != 1 {
+		t.Errorf("expected 1 result with limit, got %d", len(results))
+	}
+}
+
+func TestSourceListResource_ListByType(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+
+	addMockSource(handler, "aws-source", "aws", map[string]any{"aws-regions": []any{"us-east-1"}})
+	gcpID := addMockSource(handler, "gcp-source", "gcp", map[string]any{"gcp-project-ids": []any{"project-a"}})
+
+	results := runSourceList(t, serverURL, NewSourceListResource(), &sourceResource{}, map[string]tftypes.Value{}, false, 0)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	results = runSourceList(t, serverURL, NewGCPSourceListResource(), &gcpSourceResource{}, map[string]tftypes.Value{}, true, 0)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 gcp result, got %d", len(results))
+	}
+	var model gcpSourceResourceModel
+	if diags := results[0].Resource.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("resource: %v", diags)
+	}
+	if model.ID.ValueString() != gcpID {
+		t.Errorf("id = %q, want %q", model.ID.ValueString(), gcpID)
+	}
+	if model.OvermindServiceAccountName.ValueString() == "" {
+		t.Error("overmind_service_account_name not set")
+	}
+
+	results = runSourceList(t, serverURL, NewSourceListResource(), &sourceResource{}, map[string]tftypes.Value{
+		"type": tftypes.NewValue(tftypes.String, "gcp"),
+	}, true, 0)
+	if len(results) != 1 || results[0].DisplayName != "gcp-source" {
+		t.Fatalf("expected only the gcp source, got %d results", len(results))
+	}
+}
+
+func TestSourceListResource_OmitsCredentials(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+
+	addMockSource(handler, "ci-aws", "aws", map[string]any{
+		"aws-access-strategy":   "access-key",
+		"aws-access-key-id":     "AKIAEXAMPLE",
+		"aws-secret-access-key": "super-secret",
+		"aws-regions":           []any{"us-east-1"},
+	})
+
+	results := runSourceList(t, serverURL, NewSourceListResource(), &sourceResource{}, map[string]tftypes.Value{}, true, 0)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	var model sourceResourceModel
+	if diags := results[0].Resource.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("resource: %v", diags)
+	}
+	config, err := structFromDynamic(context.Background(), model.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.GetFields()["aws-secret-access-key"]; ok {
+		t.Error("aws-secret-access-key was copied into the listed resource")
+	}
+	if got := config.GetFields()["aws-access-key-id"].GetStringValue(); got != "AKIAEXAMPLE" {
+		t.Errorf("aws-access-key-id = %q", got)
+	}
+}
+
+func TestWithoutSensitiveConfig(t *testing.T) {
+	config, _ := structpb.NewStruct(map[string]any{
+		"aws-access-key-id":     "AKIAEXAMPLE",
+		"aws-secret-access-key": "from-api",
+	})
+
+	if got := withoutSensitiveConfig(config, nil).GetFields(); len(got) != 1 || got["aws-secret-access-key"] != nil {
+		t.Errorf("expected the secret to be removed, got %v", got)
+	}
+
+	// A secret set in config keeps its configured value on read.
+	prior, _ := structpb.NewStruct(map[string]any{"aws-secret-access-key": "configured"})
+	if got := withoutSensitiveConfig(config, prior).GetFields()["aws-secret-access-key"].GetStringValue(); got != "configured" {
+		t.Errorf("secret = %q, want the configured value", got)
+	}
+	if config.GetFields()["aws-secret-access-key"].GetStringValue() != "from-api" {
+		t.Error("the API config was modified")
+	}
+}
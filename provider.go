@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,12 +21,31 @@ import (
 	"golang.org/x/oauth2"
 )
 
-var _ provider.Provider = (*overmindProvider)(nil)
+var (
+	_ provider.Provider                  = (*overmindProvider)(nil)
+	_ provider.ProviderWithListResources = (*overmindProvider)(nil)
+	_ provider.ProviderWithActions       = (*overmindProvider)(nil)
+)
 
 type overmindProvider struct {
 	version string
 }
 
+// overmindClients is the provider data handed to every resource, data source
+// and list resource: API clients for the configured Overmind instance, plus
+// the instance itself for anything derived from its URLs.
+type overmindClients struct {
+	Instance      sdp.OvermindInstance
+	Management    sdpconnect.ManagementServiceClient
+	APIKeys       sdpconnect.ApiKeyServiceClient
+	Changes       sdpconnect.ChangesServiceClient
+	Labels        sdpconnect.LabelServiceClient
+	Configuration sdpconnect.ConfigurationServiceClient
+	Invites       sdpconnect.InviteServiceClient
+	Bookmarks     sdpconnect.BookmarksServiceClient
+	Snapshots     sdpconnect.SnapshotsServiceClient
+}
+
 type overmindProviderModel struct {
 	AppURL types.String `tfsdk:"app_url"`
 	APIKey types.String `tfsdk:"api_key"`
@@ -113,20 +134,70 @@ func (p *overmindProvider) Configure(ctx context.Context, req provider.Configure
 		Base:   httpClient.Transport,
 	}
 
-	mgmtClient := sdpconnect.NewManagementServiceClient(httpClient, apiURL)
+	clients := &overmindClients{
+		Instance:      oi,
+		Management:    sdpconnect.NewManagementServiceClient(httpClient, apiURL),
+		APIKeys:       sdpconnect.NewApiKeyServiceClient(httpClient, apiURL),
+		Changes:       sdpconnect.NewChangesServiceClient(httpClient, apiURL),
+		Labels:        sdpconnect.NewLabelServiceClient(httpClient, apiURL),
+		Configuration: sdpconnect.NewConfigurationServiceClient(httpClient, apiURL),
+		Invites:       sdpconnect.NewInviteServiceClient(httpClient, apiURL),
+		Bookmarks:     sdpconnect.NewBookmarksServiceClient(httpClient, apiURL),
+		Snapshots:     sdpconnect.NewSnapshotsServiceClient(httpClient, apiURL),
+	}
 
-	resp.DataSourceData = mgmtClient
-	resp.ResourceData = mgmtClient
+	resp.DataSourceData = clients
+	resp.ResourceData = clients
+	resp.ListResourceData = clients
+	resp.ActionData = clients
 }
 
 func (p *overmindProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAWSSourceResource,
+		NewSourceResource,
+		NewGCPSourceResource,
+		NewAPIKeyResource,
+		NewLabelRuleResource,
+		NewAccountConfigResource,
+		NewSignalConfigResource,
+		NewHCPTerraformIntegrationResource,
+		NewTeamInviteResource,
+		NewGithubAppInstallationResource,
+		NewBookmarkResource,
+		NewSnapshotResource,
+		NewChangeResource,
+		NewChangeAnalysisResource,
+	}
+}
+
+func (p *overmindProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewAWSSourceListResource,
+		NewSourceListResource,
+		NewGCPSourceListResource,
+	}
+}
+
+func (p *overmindProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewReapplyLabelRuleAction,
+		NewRegenerateGithubAppProfileAction,
+		NewStartChangeAction,
+		NewEndChangeAction,
 	}
 }
 
 func (p *overmindProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAWSExternalIdDataSource,
+		NewSourceStatusDataSource,
+		NewSourcesDataSource,
+		NewAWSIAMTrustPolicyDataSource,
+		NewAPIKeysDataSource,
+		NewTeamMembersDataSource,
+		NewBookmarksDataSource,
+		NewSnapshotDataSource,
+		NewChangeRisksDataSource,
 	}
 }
@@ -2,42 +2,69 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"regexp"
 	"sync"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
 	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
 	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // --- mock ManagementService handler ---
 
 type mockMgmtHandler struct {
 	sdpconnect.UnimplementedManagementServiceHandler
-	mu         sync.Mutex
-	sources    map[string]*sdp.Source
-	externalID string
+	mu          sync.Mutex
+	sources     map[string]*sdp.Source
+	externalID  string
+	accountName string
+	members     []*sdp.TeamMember
+	// githubInstallationID is set by SetGithubInstallationID and reported
+	// by the ConfigurationService mock.
+	githubInstallationID int64
+	// health overrides the status reported by GetSourceStatus for every
+	// source. When nil, all sources report STATUS_HEALTHY.
+	health *sdp.SourceHealth
 }
 
 func newMockMgmtHandler() *mockMgmtHandler {
 	return &mockMgmtHandler{
-		sources:    make(map[string]*sdp.Source),
-		externalID: "test-external-id-12345",
+		sources:     make(map[string]*sdp.Source),
+		externalID:  "test-external-id-12345",
+		accountName: "8d7e4f5a-1b2c-4d3e-9f80-a1b2c3d4e5f6",
 	}
 }
 
+func (m *mockMgmtHandler) GetAccount(_ context.Context, _ *connect.Request[sdp.GetAccountRequest]) (*connect.Response[sdp.GetAccountResponse], error) {
+	return connect.NewResponse(&sdp.GetAccountResponse{
+		Account: &sdp.Account{
+			Properties: &sdp.AccountProperties{Name: m.accountName},
+		},
+	}), nil
+}
+
 func (m *mockMgmtHandler) GetOrCreateAWSExternalId(_ context.Context, _ *connect.Request[sdp.GetOrCreateAWSExternalIdRequest]) (*connect.Response[sdp.GetOrCreateAWSExternalIdResponse], error) {
 	return connect.NewResponse(&sdp.GetOrCreateAWSExternalIdResponse{
 		AwsExternalId: m.externalID,
@@ -56,6 +83,16 @@ func (m *mockMgmtHandler) CreateSource(_ context.Context, req *connect.Request[s
 	return connect.NewResponse(&sdp.CreateSourceResponse{Source: source}), nil
 }
 
+func (m *mockMgmtHandler) ListSources(_ context.Context, _ *connect.Request[sdp.ListSourcesRequest]) (*connect.Response[sdp.ListSourcesResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sources := make([]*sdp.Source, 0, len(m.sources))
+	for _, source := range m.sources {
+		sources = append(sources, source)
+	}
+	return connect.NewResponse(&sdp.ListSourcesResponse{Sources: sources}), nil
+}
+
 func (m *mockMgmtHandler) GetSource(_ context.Context, req *connect.Request[sdp.GetSourceRequest]) (*connect.Response[sdp.GetSourceResponse], error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -85,6 +122,58 @@ func (m *mockMgmtHandler) UpdateSource(_ context.Context, req *connect.Request[s
 	return connect.NewResponse(&sdp.UpdateSourceResponse{Source: source}), nil
 }
 
+func (m *mockMgmtHandler) GetSourceStatus(_ context.Context, req *connect.Request[sdp.GetSourceStatusRequest]) (*connect.Response[sdp.GetSourceStatusResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, err := uuid.FromBytes(req.Msg.GetSourceUuid())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	source, ok := m.sources[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return connect.NewResponse(&sdp.GetSourceStatusResponse{Source: m.healthFor(id, source)}), nil
+}
+
+func (m *mockMgmtHandler) ListAllSourcesStatus(_ context.Context, _ *connect.Request[sdp.ListAllSourcesStatusRequest]) (*connect.Response[sdp.ListAllSourcesStatusResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	healths := make([]*sdp.SourceHealth, 0, len(m.sources))
+	for key, source := range m.sources {
+		healths = append(healths, m.healthFor(uuid.MustParse(key), source))
+	}
+	return connect.NewResponse(&sdp.ListAllSourcesStatusResponse{Sources: healths}), nil
+}
+
+// healthFor builds the SourceHealth reported for a source. m.mu must be held.
+func (m *mockMgmtHandler) healthFor(id uuid.UUID, source *sdp.Source) *sdp.SourceHealth {
+	if m.health != nil {
+		health := proto.Clone(m.health).(*sdp.SourceHealth) //nolint:forcetypeassert // clone of the same type
+		health.UUID = id[:]
+		return health
+	}
+	return &sdp.SourceHealth{
+		UUID:            id[:],
+		Name:            source.GetProperties().GetDescriptiveName(),
+		Type:            source.GetProperties().GetType(),
+		Status:          sdp.SourceStatus_STATUS_HEALTHY,
+		LastHeartbeat:   timestamppb.Now(),
+		Version:         "1.2.3",
+		Managed:         sdp.SourceManaged_MANAGED,
+		AvailableScopes: []string{"123456789012.us-east-1"},
+		AdapterMetadata: []*sdp.AdapterMetadata{{
+			Type:            "ec2-instance",
+			Category:        sdp.AdapterCategory_ADAPTER_CATEGORY_COMPUTE_APPLICATION,
+			DescriptiveName: "EC2 Instance",
+			SupportedQueryMethods: &sdp.AdapterSupportedQueryMethods{
+				Get:  true,
+				List: true,
+			},
+		}},
+	}
+}
+
 func (m *mockMgmtHandler) DeleteSource(_ context.Context, req *connect.Request[sdp.DeleteSourceRequest]) (*connect.Response[sdp.DeleteSourceResponse], error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -114,9 +203,11 @@ var _ provider.Provider = (*testProvider)(nil)
 func (p *testProvider) Configure(ctx context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	httpClient := oauth2.NewClient(ctx,
 		oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"}))
-	mgmtClient := sdpconnect.NewManagementServiceClient(httpClient, p.serverURL)
-	resp.DataSourceData = mgmtClient
-	resp.ResourceData = mgmtClient
+	clients := testClients(httpClient, p.serverURL)
+	resp.DataSourceData = clients
+	resp.ResourceData = clients
+	resp.ListResourceData = clients
+	resp.ActionData = clients
 }
 
 func (p *testProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -129,21 +220,114 @@ func (p *testProvider) Resources(ctx context.Context) []func() resource.Resource
 	return p.overmindProvider.Resources(ctx)
 }
 
+func (p *testProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return p.overmindProvider.ListResources(ctx)
+}
+
+func (p *testProvider) Actions(ctx context.Context) []func() action.Action {
+	return p.overmindProvider.Actions(ctx)
+}
+
 func (p *testProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return p.overmindProvider.DataSources(ctx)
 }
 
 // --- test helpers ---
 
+// testClients builds the provider data for the mock server, as if the
+// provider had been configured against the production instance.
+func testClients(httpClient *http.Client, serverURL string) *overmindClients {
+	frontendURL, _ := url.Parse("https://app.overmind.tech")
+	apiURL, _ := url.Parse(serverURL)
+	return &overmindClients{
+		Instance: sdp.OvermindInstance{
+			FrontendUrl: frontendURL,
+			ApiUrl:      apiURL,
+		},
+		Management:    sdpconnect.NewManagementServiceClient(httpClient, serverURL),
+		APIKeys:       sdpconnect.NewApiKeyServiceClient(httpClient, serverURL),
+		Changes:       sdpconnect.NewChangesServiceClient(httpClient, serverURL),
+		Labels:        sdpconnect.NewLabelServiceClient(httpClient, serverURL),
+		Configuration: sdpconnect.NewConfigurationServiceClient(httpClient, serverURL),
+		Invites:       sdpconnect.NewInviteServiceClient(httpClient, serverURL),
+		Bookmarks:     sdpconnect.NewBookmarksServiceClient(httpClient, serverURL),
+		Snapshots:     sdpconnect.NewSnapshotsServiceClient(httpClient, serverURL),
+	}
+}
+
 func startTestServer(t *testing.T) string {
 	t.Helper()
-	handler := newMockMgmtHandler()
-	path, h := sdpconnect.NewManagementServiceHandler(handler)
+	serverURL, _ := startTestServerWithHandler(t)
+	return serverURL
+}
+
+// startTestServerWithHandler is like startTestServer but also returns the mock
+// handler so tests can simulate changes made outside of Terraform.
+func startTestServerWithHandler(t *testing.T) (string, *mockMgmtHandler) {
+	t.Helper()
+	serverURL, mocks := startMockServer(t)
+	return serverURL, mocks.mgmt
+}
+
+// mockServer holds the mock handler for each service served by the test
+// server.
+type mockServer struct {
+	mgmt      *mockMgmtHandler
+	apiKeys   *mockAPIKeyHandler
+	changes   *mockChangesHandler
+	labels    *mockLabelHandler
+	config    *mockConfigHandler
+	invites   *mockInviteHandler
+	bookmarks *mockBookmarksHandler
+	snapshots *mockSnapshotsHandler
+}
+
+// startMockServer starts a test server for every mocked service and returns
+// the handlers so tests can simulate changes made outside of Terraform.
+func startMockServer(t *testing.T) (string, *mockServer) {
+	t.Helper()
+	changes := newMockChangesHandler()
+	mgmt := newMockMgmtHandler()
+	mocks := &mockServer{
+		mgmt:      mgmt,
+		apiKeys:   newMockAPIKeyHandler(),
+		changes:   changes,
+		labels:    newMockLabelHandler(changes),
+		config:    newMockConfigHandler(mgmt),
+		invites:   newMockInviteHandler(),
+		bookmarks: newMockBookmarksHandler(),
+		snapshots: newMockSnapshotsHandler(),
+	}
 	mux := http.NewServeMux()
-	mux.Handle(path, h)
+	mux.Handle(sdpconnect.NewManagementServiceHandler(mocks.mgmt))
+	mux.Handle(sdpconnect.NewApiKeyServiceHandler(mocks.apiKeys))
+	mux.Handle(sdpconnect.NewChangesServiceHandler(mocks.changes))
+	mux.Handle(sdpconnect.NewLabelServiceHandler(mocks.labels))
+	mux.Handle(sdpconnect.NewConfigurationServiceHandler(mocks.config))
+	mux.Handle(sdpconnect.NewInviteServiceHandler(mocks.invites))
+	mux.Handle(sdpconnect.NewBookmarksServiceHandler(mocks.bookmarks))
+	mux.Handle(sdpconnect.NewSnapshotsServiceHandler(mocks.snapshots))
 	srv := httptest.NewServer(mux)
 	t.Cleanup(srv.Close)
-	return srv.URL
+	return srv.URL, mocks
+}
+
+// addMockSource stores a source directly in the mock, as if it had been
+// created outside of Terraform, and returns its UUID.
+func addMockSource(m *mockMgmtHandler, name, sourceType string, config map[string]any) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New()
+	cfg, _ := structpb.NewStruct(config)
+	m.sources[id.String()] = &sdp.Source{
+		Metadata: &sdp.SourceMetadata{UUID: id[:]},
+		Properties: &sdp.SourceProperties{
+			DescriptiveName: name,
+			Type:            sourceType,
+			Config:          cfg,
+		},
+	}
+	return id.String()
 }
 
 func unitTestProviderFactories(serverURL string) map[string]func() (tfprotov6.ProviderServer, error) {
@@ -196,6 +380,621 @@ func TestAWSSourceResource_CRUD(t *testing.T) {
 	})
 }
 
+func TestAWSSourceResource_UpdatePreservesUnmanagedConfig(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccAWSSourceConfig("test-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+			},
+			{
+				PreConfig: func() {
+					handler.mu.Lock()
+					defer handler.mu.Unlock()
+					for _, source := range handler.sources {
+						source.Properties.Config.Fields["custom-setting"] = structpb.NewStringValue("from-the-ui")
+						source.Properties.AdditionalConfig = &structpb.Struct{Fields: map[string]*structpb.Value{
+							"extra": structpb.NewBoolValue(true),
+						}}
+					}
+				},
+				Config: testAccAWSSourceConfig("renamed-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "name", "renamed-source"),
+					func(_ *terraform.State) error {
+						handler.mu.Lock()
+						defer handler.mu.Unlock()
+						for id, source := range handler.sources {
+							props := source.GetProperties()
+							if got := props.GetConfig().GetFields()["custom-setting"].GetStringValue(); got != "from-the-ui" {
+								return fmt.Errorf("source %s lost custom-setting, got %q", id, got)
+							}
+							if !props.GetAdditionalConfig().GetFields()["extra"].GetBoolValue() {
+								return fmt.Errorf("source %s lost its additional config", id)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAWSSourceResource_WaitForHealthy(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+	handler.health = &sdp.SourceHealth{
+		Status: sdp.SourceStatus_STATUS_UNHEALTHY,
+		Error:  proto.String("AccessDenied: not authorized to perform sts:AssumeRole"),
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config:      testAccAWSSourceConfig("test-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+				ExpectError: regexp.MustCompile(`not authorized to perform sts:AssumeRole`),
+			},
+		},
+	})
+}
+
+func TestAWSSourceResource_WaitForHealthyTimeout(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+	handler.health = &sdp.SourceHealth{Status: sdp.SourceStatus_STATUS_PROGRESSING}
+
+	prev := sourceHealthPollInterval
+	sourceHealthPollInterval = 100 * time.Millisecond
+	t.Cleanup(func() { sourceHealthPollInterval = prev })
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name         = "test-source"
+  aws_role_arn = "arn:aws:iam::123456789012:role/test"
+  aws_regions  = ["us-east-1"]
+
+  timeouts {
+    create = "1s"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`timed out after 1s`),
+			},
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name             = "test-source"
+  aws_role_arn     = "arn:aws:iam::123456789012:role/test"
+  aws_regions      = ["us-east-1"]
+  wait_for_healthy = false
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "wait_for_healthy", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestWaitForSourceHealthy_IgnoresStatusFromBeforeUpdate(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+	ctx := context.Background()
+	clients := testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL)
+
+	prev := sourceHealthPollInterval
+	sourceHealthPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { sourceHealthPollInterval = prev })
+
+	createResp, err := handler.CreateSource(ctx, connect.NewRequest(&sdp.CreateSourceRequest{
+		Properties: &sdp.SourceProperties{DescriptiveName: "test-source", Type: awsSourceType},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sourceUUID := createResp.Msg.GetSource().GetMetadata().GetUUID()
+
+	// The source was healthy with its old config and has not reported in
+	// since the update. Overmind's clock is behind the local one.
+	previous := time.Now().Add(-2 * time.Minute)
+	handler.mu.Lock()
+	handler.health = &sdp.SourceHealth{
+		Status:        sdp.SourceStatus_STATUS_HEALTHY,
+		LastHeartbeat: timestamppb.New(previous),
+	}
+	handler.mu.Unlock()
+
+	lastHeartbeat, err := lastSourceHeartbeat(ctx, clients.Management, sourceUUID)
+	if err != nil || !lastHeartbeat.Equal(previous) {
+		t.Fatalf("lastSourceHeartbeat = %v, %v, want %v", lastHeartbeat, err, previous)
+	}
+	_, err = waitForSourceHealthy(ctx, clients.Management, sourceUUID, lastHeartbeat, 100*time.Millisecond)
+	if err == nil || !regexp.MustCompile(`waiting for source to report in after the update`).MatchString(err.Error()) {
+		t.Fatalf("expected the stale status to be ignored, got %v", err)
+	}
+
+	// Without a previous heartbeat the same status counts, as it does on
+	// create.
+	if _, err := waitForSourceHealthy(ctx, clients.Management, sourceUUID, time.Time{}, 100*time.Millisecond); err != nil {
+		t.Fatalf("expected healthy on create, got %v", err)
+	}
+
+	// A newer heartbeat is trusted, including its error, even though it is
+	// still behind the local clock.
+	handler.mu.Lock()
+	handler.health = &sdp.SourceHealth{
+		Status:        sdp.SourceStatus_STATUS_UNHEALTHY,
+		Error:         proto.String("AccessDenied: not authorized to perform sts:AssumeRole"),
+		LastHeartbeat: timestamppb.New(previous.Add(time.Minute)),
+	}
+	handler.mu.Unlock()
+	_, err = waitForSourceHealthy(ctx, clients.Management, sourceUUID, lastHeartbeat, time.Second)
+	if !errors.Is(err, errSourceUnhealthy) {
+		t.Fatalf("expected the new config's error, got %v", err)
+	}
+
+	// A source that has not registered yet has no previous heartbeat.
+	missing := uuid.New()
+	if lastHeartbeat, err := lastSourceHeartbeat(ctx, clients.Management, missing[:]); err != nil || !lastHeartbeat.IsZero() {
+		t.Errorf("lastSourceHeartbeat = %v, %v, want the zero time", lastHeartbeat, err)
+	}
+}
+
+func TestAWSSourceResource_AccessStrategies(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccAWSSourceConfig("test-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "access_strategy", "external-id"),
+				),
+			},
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name            = "test-source"
+  access_strategy = "sso-profile"
+  aws_regions     = ["us-east-1"]
+
+  sso_profile {
+    profile = "platform-readonly"
+  }
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "access_strategy", "sso-profile"),
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "sso_profile.profile", "platform-readonly"),
+					tfresource.TestCheckNoResourceAttr("overmind_aws_source.test", "aws_role_arn"),
+				),
+			},
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name            = "test-source"
+  access_strategy = "access-key"
+  aws_regions     = ["us-east-1"]
+
+  access_key {
+    access_key_id                = "AKIAEXAMPLE"
+    secret_access_key_wo         = "super-secret"
+    secret_access_key_wo_version = 1
+  }
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "access_strategy", "access-key"),
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "access_key.access_key_id", "AKIAEXAMPLE"),
+					tfresource.TestCheckNoResourceAttr("overmind_aws_source.test", "access_key.secret_access_key_wo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAWSSourceResource_AccessStrategyValidation(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name        = "test-source"
+  aws_regions = ["us-east-1"]
+}`,
+				ExpectError: regexp.MustCompile(`Missing access strategy configuration`),
+			},
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name            = "test-source"
+  access_strategy = "defaults"
+  aws_role_arn    = "arn:aws:iam::123456789012:role/test"
+  aws_regions     = ["us-east-1"]
+}`,
+				ExpectError: regexp.MustCompile(`Conflicting access strategy configuration`),
+			},
+			{
+				Config: `resource "overmind_aws_source" "test" {
+  name            = "test-source"
+  access_strategy = "magic"
+  aws_regions     = ["us-east-1"]
+}`,
+				ExpectError: regexp.MustCompile(`Invalid access strategy`),
+			},
+		},
+	})
+}
+
+func TestSourceResource_CRUD(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_source" "test" {
+  name = "gcp-source"
+  type = "gcp"
+  config = {
+    "gcp-project-id" = "my-project"
+    "gcp-regions"    = ["us-central1", "europe-west1"]
+    nested = {
+      enabled = true
+      depth   = 3
+    }
+  }
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_source.test", "id"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "name", "gcp-source"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "type", "gcp"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "config.gcp-project-id", "my-project"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "config.gcp-regions.#", "2"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "config.nested.depth", "3"),
+				),
+			},
+			{
+				Config: `resource "overmind_source" "test" {
+  name = "gcp-source-renamed"
+  type = "gcp"
+  config = {
+    "gcp-project-id" = "my-project"
+    "gcp-regions"    = ["us-central1"]
+    nested = {
+      enabled = false
+      depth   = 3
+    }
+  }
+  additional_config = {
+    note = "managed by terraform"
+  }
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_source.test", "name", "gcp-source-renamed"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "config.gcp-regions.#", "1"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "config.nested.enabled", "false"),
+					tfresource.TestCheckResourceAttr("overmind_source.test", "additional_config.note", "managed by terraform"),
+				),
+			},
+			{
+				ResourceName:      "overmind_source.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSourceResource_ConfigMustBeObject(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_source" "test" {
+  name   = "bad"
+  type   = "gcp"
+  config = ["not", "an", "object"]
+}`,
+				ExpectError: regexp.MustCompile(`Invalid source configuration`),
+			},
+		},
+	})
+}
+
+func TestGCPSourceResource_CRUD(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccGCPSourceConfig("gcp-source", `["project-a", "project-b"]`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_gcp_source.test", "id"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "name", "gcp-source"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "gcp_project_ids.#", "2"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "gcp_regions.#", "1"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "overmind_service_account_name",
+						"C-8d7e4f5a1b2c4d3e9f80a1b2c3d4"),
+				),
+			},
+			{
+				Config: testAccGCPSourceConfig("gcp-source-renamed", `["project-a"]`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "name", "gcp-source-renamed"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "gcp_project_ids.#", "1"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "gcp_project_ids.0", "project-a"),
+				),
+			},
+			{
+				ResourceName:      "overmind_gcp_source.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGCPSourceResource_UpdatePreservesUnmanagedConfig(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccGCPSourceConfig("gcp-source", `["project-a"]`),
+			},
+			{
+				PreConfig: func() {
+					handler.mu.Lock()
+					defer handler.mu.Unlock()
+					for _, source := range handler.sources {
+						source.Properties.Config.Fields["custom-setting"] = structpb.NewStringValue("from-the-ui")
+						source.Properties.AdditionalConfig = &structpb.Struct{Fields: map[string]*structpb.Value{
+							"extra": structpb.NewBoolValue(true),
+						}}
+					}
+				},
+				Config: testAccGCPSourceConfig("renamed-gcp-source", `["project-a", "project-b"]`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "name", "renamed-gcp-source"),
+					tfresource.TestCheckResourceAttr("overmind_gcp_source.test", "gcp_project_ids.#", "2"),
+					func(_ *terraform.State) error {
+						handler.mu.Lock()
+						defer handler.mu.Unlock()
+						for id, source := range handler.sources {
+							props := source.GetProperties()
+							if got := props.GetConfig().GetFields()["custom-setting"].GetStringValue(); got != "from-the-ui" {
+								return fmt.Errorf("source %s lost custom-setting, got %q", id, got)
+							}
+							if !props.GetAdditionalConfig().GetFields()["extra"].GetBoolValue() {
+								return fmt.Errorf("source %s lost its additional config", id)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestGCPSourceResource_ImportRejectsOtherTypes(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccAWSSourceConfig("aws-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+			},
+			{
+				Config:       testAccAWSSourceConfig("aws-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`),
+				ResourceName: "overmind_gcp_source.imported",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources["overmind_aws_source.test"].Primary.ID, nil
+				},
+				ExpectError: regexp.MustCompile(`Unexpected source type`),
+			},
+		},
+	})
+}
+
+func TestAWSSourceResource_ImportByName(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	config := `
+resource "overmind_aws_source" "unique" {
+  name         = "unique"
+  aws_role_arn = "arn:aws:iam::123456789012:role/unique"
+  aws_regions  = ["us-east-1"]
+}
+
+resource "overmind_aws_source" "dup_a" {
+  name         = "dup"
+  aws_role_arn = "arn:aws:iam::123456789012:role/a"
+  aws_regions  = ["us-east-1"]
+}
+
+resource "overmind_aws_source" "dup_b" {
+  name         = "dup"
+  aws_role_arn = "arn:aws:iam::123456789012:role/b"
+  aws_regions  = ["us-east-1"]
+}
+
+resource "overmind_gcp_source" "gcp" {
+  name                                = "gcp-only"
+  gcp_project_ids                     = ["project-a"]
+  impersonation_service_account_email = "overmind@project-a.iam.gserviceaccount.com"
+}`
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:            config,
+				ResourceName:      "overmind_aws_source.unique",
+				ImportState:       true,
+				ImportStateId:     "name:unique",
+				ImportStateVerify: true,
+			},
+			{
+				Config:          config,
+				ResourceName:    "overmind_aws_source.unique",
+				ImportState:     true,
+				ImportStateKind: tfresource.ImportBlockWithResourceIdentity,
+			},
+			{
+				Config:        config,
+				ResourceName:  "overmind_aws_source.dup_a",
+				ImportState:   true,
+				ImportStateId: "name:dup",
+				ExpectError:   regexp.MustCompile(`Ambiguous source name`),
+			},
+			{
+				Config:        config,
+				ResourceName:  "overmind_aws_source.unique",
+				ImportState:   true,
+				ImportStateId: "name:gcp-only",
+				ExpectError:   regexp.MustCompile(`Source not found`),
+			},
+			{
+				Config:       config,
+				ResourceName: "overmind_aws_source.unique",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources["overmind_gcp_source.gcp"].Primary.ID, nil
+				},
+				ExpectError: regexp.MustCompile(`Unexpected source type`),
+			},
+			{
+				Config:        config,
+				ResourceName:  "overmind_aws_source.unique",
+				ImportState:   true,
+				ImportStateId: "not-a-uuid",
+				ExpectError:   regexp.MustCompile(`Invalid source ID`),
+			},
+		},
+	})
+}
+
+func TestFindSourceByName(t *testing.T) {
+	serverURL, handler := startTestServerWithHandler(t)
+	mgmt := sdpconnect.NewManagementServiceClient(http.DefaultClient, serverURL)
+	ctx := context.Background()
+
+	awsID := addMockSource(handler, "prod", "aws", nil)
+	addMockSource(handler, "prod", "gcp", nil)
+	addMockSource(handler, "dup", "aws", nil)
+	addMockSource(handler, "dup", "aws", nil)
+
+	source, diags := findSourceByName(ctx, mgmt, "aws", "prod")
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if id, _ := uuid.FromBytes(source.GetMetadata().GetUUID()); id.String() != awsID {
+		t.Errorf("got source %s, want %s", id, awsID)
+	}
+
+	if _, diags := findSourceByName(ctx, mgmt, "", "prod"); !diags.HasError() || diags[0].Summary() != "Ambiguous source name" {
+		t.Errorf("expected ambiguity across types, got %v", diags)
+	}
+	if _, diags := findSourceByName(ctx, mgmt, "aws", "dup"); !diags.HasError() || diags[0].Summary() != "Ambiguous source name" {
+		t.Errorf("expected ambiguity, got %v", diags)
+	}
+	if _, diags := findSourceByName(ctx, mgmt, "aws", "missing"); !diags.HasError() || diags[0].Summary() != "Source not found" {
+		t.Errorf("expected not found, got %v", diags)
+	}
+}
+
+func TestSourceStatusDataSource_Read(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccAWSSourceConfig("test-source", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`) + `
+
+data "overmind_source_status" "all" {
+  depends_on = [overmind_aws_source.test]
+}
+
+data "overmind_source_status" "one" {
+  id = overmind_aws_source.test.id
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "health.status", "STATUS_HEALTHY"),
+					tfresource.TestCheckResourceAttr("overmind_aws_source.test", "health.available_scopes.0", "123456789012.us-east-1"),
+					tfresource.TestCheckResourceAttr("data.overmind_source_status.all", "all_healthy", "true"),
+					tfresource.TestCheckResourceAttr("data.overmind_source_status.all", "sources.#", "1"),
+					tfresource.TestCheckResourceAttrPair(
+						"data.overmind_source_status.one", "sources.0.id", "overmind_aws_source.test", "id"),
+					tfresource.TestCheckResourceAttr("data.overmind_source_status.one", "sources.0.version", "1.2.3"),
+					tfresource.TestCheckResourceAttr("data.overmind_source_status.one", "sources.0.adapters.0.type", "ec2-instance"),
+					tfresource.TestCheckResourceAttr("data.overmind_source_status.one", "sources.0.adapters.0.supports_search", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestSourcesDataSource_Filters(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	sources := testAccAWSSourceConfig("prod-aws", "arn:aws:iam::123456789012:role/test", `["us-east-1"]`) +
+		"\n" + testAccGCPSourceConfig("prod-gcp", `["project-a"]`) + `
+
+resource "overmind_aws_source" "staging" {
+  name         = "staging-aws"
+  aws_role_arn = "arn:aws:iam::210987654321:role/test"
+  aws_regions  = ["eu-west-1"]
+}
+`
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: sources,
+			},
+			{
+				Config: sources + `
+data "overmind_sources" "all" {}
+
+data "overmind_sources" "aws_prod" {
+  type       = "aws"
+  name_regex = "^prod-"
+  status     = "STATUS_HEALTHY"
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_sources.all", "sources.#", "3"),
+					tfresource.TestCheckResourceAttr("data.overmind_sources.all", "sources.0.name", "prod-aws"),
+					tfresource.TestCheckResourceAttr("data.overmind_sources.aws_prod", "sources.#", "1"),
+					tfresource.TestCheckResourceAttrPair(
+						"data.overmind_sources.aws_prod", "ids.0", "overmind_aws_source.test", "id"),
+					tfresource.TestCheckResourceAttr("data.overmind_sources.aws_prod", "sources.0.status", "STATUS_HEALTHY"),
+					tfresource.TestMatchResourceAttr("data.overmind_sources.aws_prod", "sources.0.config_json",
+						regexp.MustCompile(`"aws-target-role-arn":"arn:aws:iam::123456789012:role/test"`)),
+				),
+			},
+			{
+				Config: `data "overmind_sources" "bad" {
+  status = "HEALTHY"
+}`,
+				ExpectError: regexp.MustCompile(`Invalid source status`),
+			},
+		},
+	})
+}
+
 func TestProviderConfigure_MissingAPIKey(t *testing.T) {
 	t.Setenv("OVERMIND_API_KEY", "")
 	t.Setenv("OVERMIND_APP_URL", "")
@@ -241,3 +1040,12 @@ func testAccAWSSourceConfig(name, roleARN, regions string) string {
   aws_regions  = ` + regions + `
 }`
 }
+
+func testAccGCPSourceConfig(name, projectIDs string) string {
+	return `resource "overmind_gcp_source" "test" {
+  name                                = "` + name + `"
+  gcp_project_ids                     = ` + projectIDs + `
+  gcp_regions                         = ["us-central1"]
+  impersonation_service_account_email = "overmind@project-a.iam.gserviceaccount.com"
+}`
+}
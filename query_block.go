@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+)
+
+// queryModel backs the `query {}` blocks of resources that store a set of
+// SDP queries, such as bookmarks and snapshots.
+type queryModel struct {
+	Type        types.String `tfsdk:"type"`
+	Method      types.String `tfsdk:"method"`
+	Query       types.String `tfsdk:"query"`
+	Scope       types.String `tfsdk:"scope"`
+	LinkDepth   types.Int64  `tfsdk:"link_depth"`
+	IgnoreCache types.Bool   `tfsdk:"ignore_cache"`
+}
+
+func queryBlock(description string, planModifiers ...planmodifier.List) schema.Block {
+	return schema.ListNestedBlock{
+		Description:   description,
+		PlanModifiers: planModifiers,
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					Description: "Type of item to query, e.g. \"ec2-instance\", or \"*\" for all types.",
+					Required:    true,
+				},
+				"method": schema.StringAttribute{
+					Description: "Query method: GET, LIST or SEARCH. Defaults to GET.",
+					Optional:    true,
+					Computed:    true,
+					Default:     stringdefault.StaticString(sdp.QueryMethod_GET.String()),
+				},
+				"query": schema.StringAttribute{
+					Description: "Query passed to the method, e.g. the item's unique attribute for GET. Required for GET.",
+					Optional:    true,
+				},
+				"scope": schema.StringAttribute{
+					Description: "Scope to query, e.g. an AWS account and region, or \"*\" for all scopes.",
+					Required:    true,
+				},
+				"link_depth": schema.Int64Attribute{
+					Description: "How many levels of linked items to follow. Defaults to 0, which returns only the items found.",
+					Optional:    true,
+					Computed:    true,
+					Default:     int64default.StaticInt64(0),
+				},
+				"ignore_cache": schema.BoolAttribute{
+					Description: "Whether sources should bypass their cache when running the query.",
+					Optional:    true,
+					Computed:    true,
+					Default:     booldefault.StaticBool(false),
+				},
+			},
+		},
+	}
+}
+
+// queryDataSourceAttribute is the read-only equivalent of queryBlock for
+// data sources.
+func queryDataSourceAttribute(description string) dsschema.Attribute {
+	return dsschema.ListNestedAttribute{
+		Description: description,
+		Computed:    true,
+		NestedObject: dsschema.NestedAttributeObject{
+			Attributes: map[string]dsschema.Attribute{
+				"type": dsschema.StringAttribute{
+					Description: "Type of item queried.",
+					Computed:    true,
+				},
+				"method": dsschema.StringAttribute{
+					Description: "Query method: GET, LIST or SEARCH.",
+					Computed:    true,
+				},
+				"query": dsschema.StringAttribute{
+					Description: "Query passed to the method.",
+					Computed:    true,
+				},
+				"scope": dsschema.StringAttribute{
+					Description: "Scope queried.",
+					Computed:    true,
+				},
+				"link_depth": dsschema.Int64Attribute{
+					Description: "How many levels of linked items are followed.",
+					Computed:    true,
+				},
+				"ignore_cache": dsschema.BoolAttribute{
+					Description: "Whether sources bypass their cache when running the query.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// toQuery converts the block to an SDP query with a fresh UUID. Every
+// attribute must be known.
+func (q queryModel) toQuery() *sdp.Query {
+	id := uuid.New()
+	return &sdp.Query{
+		Type:        q.Type.ValueString(),
+		Method:      sdp.QueryMethod(sdp.QueryMethod_value[q.Method.ValueString()]),
+		Query:       q.Query.ValueString(),
+		Scope:       q.Scope.ValueString(),
+		IgnoreCache: q.IgnoreCache.ValueBool(),
+		UUID:        id[:],
+		RecursionBehaviour: &sdp.Query_RecursionBehaviour{
+			LinkDepth: uint32(q.LinkDepth.ValueInt64()), //nolint:gosec // checked by validateQueries
+		},
+	}
+}
+
+func queryModelFromSDP(q *sdp.Query) queryModel {
+	return queryModel{
+		Type:        types.StringValue(q.GetType()),
+		Method:      types.StringValue(q.GetMethod().String()),
+		Query:       stringOrNull(q.GetQuery()),
+		Scope:       types.StringValue(q.GetScope()),
+		LinkDepth:   types.Int64Value(int64(q.GetRecursionBehaviour().GetLinkDepth())),
+		IgnoreCache: types.BoolValue(q.GetIgnoreCache()),
+	}
+}
+
+// queriesToSDP converts every block to an SDP query.
+func queriesToSDP(queries []queryModel) []*sdp.Query {
+	out := make([]*sdp.Query, len(queries))
+	for i, q := range queries {
+		out[i] = q.toQuery()
+	}
+	return out
+}
+
+func queryModelsFromSDP(queries []*sdp.Query) []queryModel {
+	out := make([]queryModel, len(queries))
+	for i, q := range queries {
+		out[i] = queryModelFromSDP(q)
+	}
+	return out
+}
+
+// validateQueries checks each fully known query block with Query.Validate,
+// reporting problems against the block at root.AtListIndex(i).
+func validateQueries(queries []queryModel, root path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, q := range queries {
+		attrPath := root.AtListIndex(i)
+		if q.Type.IsUnknown() || q.Method.IsUnknown() || q.Query.IsUnknown() || q.Scope.IsUnknown() ||
+			q.LinkDepth.IsUnknown() || q.IgnoreCache.IsUnknown() {
+			continue
+		}
+
+		if !q.Method.IsNull() {
+			if _, ok := sdp.QueryMethod_value[q.Method.ValueString()]; !ok {
+				valid := make([]string, 0, len(sdp.QueryMethod_value))
+				for name := range sdp.QueryMethod_value {
+					valid = append(valid, name)
+				}
+				slices.Sort(valid)
+				diags.AddAttributeError(attrPath.AtName("method"), "Invalid query method",
+					fmt.Sprintf("method must be one of %s, got %q.", strings.Join(valid, ", "), q.Method.ValueString()))
+				continue
+			}
+		}
+		if !q.LinkDepth.IsNull() && (q.LinkDepth.ValueInt64() < 0 || q.LinkDepth.ValueInt64() > 0xFFFFFFFF) {
+			diags.AddAttributeError(attrPath.AtName("link_depth"), "Invalid link depth",
+				fmt.Sprintf("link_depth must be between 0 and %d, got %d.", uint32(0xFFFFFFFF), q.LinkDepth.ValueInt64()))
+			continue
+		}
+
+		// The API does not tell an empty query from an unset one, so it
+		// would be read back as null.
+		if !q.Query.IsNull() && q.Query.ValueString() == "" {
+			diags.AddAttributeError(attrPath.AtName("query"), "Empty query",
+				"query cannot be an empty string. Leave it unset for methods that do not take a query.")
+			continue
+		}
+
+		// Method is null during validation when it is left to default.
+		if q.Method.IsNull() {
+			q.Method = types.StringValue(sdp.QueryMethod_GET.String())
+		}
+		if err := q.toQuery().Validate(); err != nil {
+			diags.AddAttributeError(attrPath, "Invalid query",
+				fmt.Sprintf("%s %s query for %q in scope %q is not valid: %s",
+					q.Method.ValueString(), q.Type.ValueString(), q.Query.ValueString(), q.Scope.ValueString(), queryValidationReason(err)))
+		}
+	}
+	return diags
+}
+
+// queryValidationReason strips the query dump that Query.Validate appends to
+// its errors, which is not meaningful in HCL terms.
+func queryValidationReason(err error) string {
+	msg := err.Error()
+	if i := strings.Index(msg, ":"); i >= 0 {
+		return msg[:i]
+	}
+	return msg
+}
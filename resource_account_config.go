@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// blastRadiusPresets are the presets that can be configured. UNSPECIFIED is
+// left out since the API treats it as DETAILED.
+var blastRadiusPresets = []string{ //nolint:gochecknoglobals // constant list
+	sdp.AccountConfig_QUICK.String(),
+	sdp.AccountConfig_DETAILED.String(),
+	sdp.AccountConfig_FULL.String(),
+}
+
+// defaultAccountConfig is what the account is reset to when the resource is
+// destroyed.
+func defaultAccountConfig() *sdp.AccountConfig {
+	return &sdp.AccountConfig{
+		BlastRadiusPreset:           sdp.AccountConfig_DETAILED,
+		SkipUnmappedChangesForRisks: false,
+	}
+}
+
+var blastRadiusAttrTypes = map[string]attr.Type{ //nolint:gochecknoglobals // constant schema types
+	"max_items":                       types.Int64Type,
+	"link_depth":                      types.Int64Type,
+	"change_analysis_target_duration": types.StringType,
+}
+
+var (
+	_ resource.Resource                   = (*accountConfigResource)(nil)
+	_ resource.ResourceWithImportState    = (*accountConfigResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*accountConfigResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*accountConfigResource)(nil)
+)
+
+type accountConfigResource struct {
+	mgmt   sdpconnect.ManagementServiceClient
+	config sdpconnect.ConfigurationServiceClient
+}
+
+type accountConfigResourceModel struct {
+	ID                          types.String `tfsdk:"id"`
+	BlastRadiusPreset           types.String `tfsdk:"blast_radius_preset"`
+	SkipUnmappedChangesForRisks types.Bool   `tfsdk:"skip_unmapped_changes_for_risks"`
+	BlastRadius                 types.Object `tfsdk:"blast_radius"`
+}
+
+func NewAccountConfigResource() resource.Resource {
+	return &accountConfigResource{}
+}
+
+func (r *accountConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_config"
+}
+
+func (r *accountConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the change analysis settings of the Overmind account. There is one per account, " +
+			"so declare at most one of these resources. Destroying it restores the default settings.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Name of the Overmind account the settings belong to.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"blast_radius_preset": schema.StringAttribute{
+				Description: fmt.Sprintf("How thoroughly to discover the blast radius of a change: one of %s. "+
+					"Defaults to the current setting, which is DETAILED for new accounts.", strings.Join(blastRadiusPresets, ", ")),
+				Optional: true,
+				Computed: true,
+			},
+			"skip_unmapped_changes_for_risks": schema.BoolAttribute{
+				Description: "Leave changes that could not be mapped to real infrastructure out of risk calculations. " +
+					"Defaults to the current setting, which is false for new accounts.",
+				Optional: true,
+				Computed: true,
+			},
+			"blast_radius": schema.SingleNestedAttribute{
+				Description: "Blast radius limits used for the current preset. Custom limits are not supported, " +
+					"so these are determined by blast_radius_preset.",
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"max_items": schema.Int64Attribute{
+						Description: "Maximum number of items in a blast radius.",
+						Computed:    true,
+					},
+					"link_depth": schema.Int64Attribute{
+						Description: "Maximum number of links followed from each changing item.",
+						Computed:    true,
+					},
+					"change_analysis_target_duration": schema.StringAttribute{
+						Description: "Target duration of change analysis as a Go duration string, or null if there is none.",
+						Computed:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *accountConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config accountConfigResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.BlastRadiusPreset.IsNull() || config.BlastRadiusPreset.IsUnknown() {
+		return
+	}
+
+	if _, ok := parseBlastRadiusPreset(config.BlastRadiusPreset.ValueString()); !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("blast_radius_preset"), "Invalid blast radius preset",
+			fmt.Sprintf("blast_radius_preset must be one of %s, got %q.",
+				strings.Join(blastRadiusPresets, ", "), config.BlastRadiusPreset.ValueString()))
+	}
+}
+
+// ModifyPlan keeps the blast radius limits from state unless the preset
+// changes, since they are derived from it.
+func (r *accountConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan accountConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.BlastRadiusPreset.IsUnknown() || plan.BlastRadiusPreset.Equal(state.BlastRadiusPreset) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("blast_radius"), state.BlastRadius)...)
+	}
+}
+
+func (r *accountConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+	r.config = clients.Configuration
+}
+
+func (r *accountConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountConfig Create")
+	defer span.End()
+
+	var plan accountConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accountResp, err := r.mgmt.GetAccount(ctx, connect.NewRequest(&sdp.GetAccountRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get account", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccount failed")
+		return
+	}
+	plan.ID = types.StringValue(accountResp.Msg.GetAccount().GetProperties().GetName())
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "apply failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *accountConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountConfig Read")
+	defer span.End()
+
+	var state accountConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	getResp, err := r.config.GetAccountConfig(ctx, connect.NewRequest(&sdp.GetAccountConfigRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read account config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccountConfig failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setAccountConfig(getResp.Msg.GetConfig())...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *accountConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountConfig Update")
+	defer span.End()
+
+	var plan accountConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "apply failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete restores the default settings, since the account config itself
+// cannot be removed.
+func (r *accountConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountConfig Delete")
+	defer span.End()
+
+	var state accountConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	_, err := r.config.UpdateAccountConfig(ctx, connect.NewRequest(&sdp.UpdateAccountConfigRequest{
+		Config: defaultAccountConfig(),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to restore default account config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateAccountConfig failed")
+	}
+}
+
+func (r *accountConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "AccountConfig Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.account.name", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply sends the planned settings to the API, filling in any that are not
+// configured from the current settings, and records the result in the
+// model.
+func (r *accountConfigResource) apply(ctx context.Context, plan *accountConfigResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	getResp, err := r.config.GetAccountConfig(ctx, connect.NewRequest(&sdp.GetAccountConfigRequest{}))
+	if err != nil {
+		diags.AddError("Failed to read account config", err.Error())
+		return diags
+	}
+	current := getResp.Msg.GetConfig()
+
+	config := &sdp.AccountConfig{
+		BlastRadiusPreset:           current.GetBlastRadiusPreset(),
+		SkipUnmappedChangesForRisks: current.GetSkipUnmappedChangesForRisks(),
+	}
+	if !plan.BlastRadiusPreset.IsUnknown() && !plan.BlastRadiusPreset.IsNull() {
+		// Checked by ValidateConfig.
+		config.BlastRadiusPreset, _ = parseBlastRadiusPreset(plan.BlastRadiusPreset.ValueString())
+	}
+	if !plan.SkipUnmappedChangesForRisks.IsUnknown() && !plan.SkipUnmappedChangesForRisks.IsNull() {
+		config.SkipUnmappedChangesForRisks = plan.SkipUnmappedChangesForRisks.ValueBool()
+	}
+
+	updateResp, err := r.config.UpdateAccountConfig(ctx, connect.NewRequest(&sdp.UpdateAccountConfigRequest{
+		Config: config,
+	}))
+	if err != nil {
+		diags.AddError("Failed to update account config", err.Error())
+		return diags
+	}
+
+	diags.Append(plan.setAccountConfig(updateResp.Msg.GetConfig())...)
+	return diags
+}
+
+// setAccountConfig copies the API representation of the settings into the
+// model. UNSPECIFIED is reported as DETAILED, which is how the API treats it.
+func (m *accountConfigResourceModel) setAccountConfig(config *sdp.AccountConfig) diag.Diagnostics {
+	preset := config.GetBlastRadiusPreset()
+	if preset == sdp.AccountConfig_UNSPECIFIED {
+		preset = sdp.AccountConfig_DETAILED
+	}
+	m.BlastRadiusPreset = types.StringValue(preset.String())
+	m.SkipUnmappedChangesForRisks = types.BoolValue(config.GetSkipUnmappedChangesForRisks())
+
+	if config.GetBlastRadius() == nil {
+		m.BlastRadius = types.ObjectNull(blastRadiusAttrTypes)
+		return nil
+	}
+
+	blastRadius := config.GetBlastRadius()
+	targetDuration := types.StringNull()
+	if blastRadius.GetChangeAnalysisTargetDuration() != nil {
+		targetDuration = types.StringValue(blastRadius.GetChangeAnalysisTargetDuration().AsDuration().String())
+	}
+
+	var diags diag.Diagnostics
+	m.BlastRadius, diags = types.ObjectValue(blastRadiusAttrTypes, map[string]attr.Value{
+		"max_items":                       types.Int64Value(int64(blastRadius.GetMaxItems())),
+		"link_depth":                      types.Int64Value(int64(blastRadius.GetLinkDepth())),
+		"change_analysis_target_duration": targetDuration,
+	})
+	return diags
+}
+
+// parseBlastRadiusPreset returns the preset with the given name, if it can be
+// configured.
+func parseBlastRadiusPreset(name string) (sdp.AccountConfig_BlastRadiusPreset, bool) {
+	for _, preset := range blastRadiusPresets {
+		if preset == name {
+			return sdp.AccountConfig_BlastRadiusPreset(sdp.AccountConfig_BlastRadiusPreset_value[name]), true
+		}
+	}
+	return sdp.AccountConfig_UNSPECIFIED, false
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// --- mock ConfigurationService handler ---
+
+type mockConfigHandler struct {
+	sdpconnect.UnimplementedConfigurationServiceHandler
+	mu            sync.Mutex
+	accountConfig *sdp.AccountConfig
+	signalConfig  *sdp.SignalConfig
+	hcpConfig     *sdp.HcpConfig
+	mgmt          *mockMgmtHandler
+}
+
+func newMockConfigHandler(mgmt *mockMgmtHandler) *mockConfigHandler {
+	return &mockConfigHandler{
+		mgmt:          mgmt,
+		accountConfig: &sdp.AccountConfig{BlastRadiusPreset: sdp.AccountConfig_UNSPECIFIED},
+		signalConfig:  &sdp.SignalConfig{},
+	}
+}
+
+// mockBlastRadiusLimits mirrors the server populating the limits from the
+// preset when reading.
+func mockBlastRadiusLimits(preset sdp.AccountConfig_BlastRadiusPreset) *sdp.BlastRadiusConfig {
+	switch preset {
+	case sdp.AccountConfig_QUICK:
+		return &sdp.BlastRadiusConfig{MaxItems: 100, LinkDepth: 2}
+	case sdp.AccountConfig_FULL:
+		return &sdp.BlastRadiusConfig{MaxItems: 10000, LinkDepth: 10, ChangeAnalysisTargetDuration: durationpb.New(30 * time.Minute)}
+	default:
+		return &sdp.BlastRadiusConfig{MaxItems: 1000, LinkDepth: 4, ChangeAnalysisTargetDuration: durationpb.New(10 * time.Minute)}
+	}
+}
+
+func (m *mockConfigHandler) currentAccountConfig() *sdp.AccountConfig {
+	config := proto.Clone(m.accountConfig).(*sdp.AccountConfig)
+	config.BlastRadius = mockBlastRadiusLimits(config.GetBlastRadiusPreset())
+	return config
+}
+
+func (m *mockConfigHandler) GetAccountConfig(_ context.Context, _ *connect.Request[sdp.GetAccountConfigRequest]) (*connect.Response[sdp.GetAccountConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return connect.NewResponse(&sdp.GetAccountConfigResponse{Config: m.currentAccountConfig()}), nil
+}
+
+func (m *mockConfigHandler) UpdateAccountConfig(_ context.Context, req *connect.Request[sdp.UpdateAccountConfigRequest]) (*connect.Response[sdp.UpdateAccountConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Only the preset and flags are stored; custom limits are ignored.
+	m.accountConfig = &sdp.AccountConfig{
+		BlastRadiusPreset:           req.Msg.GetConfig().GetBlastRadiusPreset(),
+		SkipUnmappedChangesForRisks: req.Msg.GetConfig().GetSkipUnmappedChangesForRisks(),
+	}
+	return connect.NewResponse(&sdp.UpdateAccountConfigResponse{Config: m.currentAccountConfig()}), nil
+}
+
+// --- tests ---
+
+func TestAccountConfigResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			mocks.config.mu.Lock()
+			defer mocks.config.mu.Unlock()
+			if mocks.config.accountConfig.GetBlastRadiusPreset() != sdp.AccountConfig_DETAILED ||
+				mocks.config.accountConfig.GetSkipUnmappedChangesForRisks() {
+				return fmt.Errorf("account config not restored to defaults: %v", mocks.config.accountConfig)
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				// Unset attributes keep the current settings.
+				Config: `resource "overmind_account_config" "this" {}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "id", "8d7e4f5a-1b2c-4d3e-9f80-a1b2c3d4e5f6"),
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "blast_radius_preset", "DETAILED"),
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "skip_unmapped_changes_for_risks", "false"),
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "blast_radius.max_items", "1000"),
+				),
+			},
+			{
+				Config: `resource "overmind_account_config" "this" {
+  blast_radius_preset             = "QUICK"
+  skip_unmapped_changes_for_risks = true
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "blast_radius_preset", "QUICK"),
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "skip_unmapped_changes_for_risks", "true"),
+					tfresource.TestCheckResourceAttr("overmind_account_config.this", "blast_radius.link_depth", "2"),
+					tfresource.TestCheckNoResourceAttr("overmind_account_config.this", "blast_radius.change_analysis_target_duration"),
+				),
+			},
+			{
+				ResourceName:      "overmind_account_config.this",
+				ImportState:       true,
+				ImportStateId:     "8d7e4f5a-1b2c-4d3e-9f80-a1b2c3d4e5f6",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccountConfigResource_InvalidPreset(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_account_config" "this" {
+  blast_radius_preset = "CUSTOM"
+}`,
+				ExpectError: regexp.MustCompile(`Invalid blast radius preset`),
+			},
+		},
+	})
+}
+
+func TestAccountConfigModel_SetAccountConfig(t *testing.T) {
+	var m accountConfigResourceModel
+	if diags := m.setAccountConfig(&sdp.AccountConfig{
+		BlastRadiusPreset: sdp.AccountConfig_UNSPECIFIED,
+		BlastRadius:       mockBlastRadiusLimits(sdp.AccountConfig_FULL),
+	}); diags.HasError() {
+		t.Fatalf("setAccountConfig: %v", diags)
+	}
+	if m.BlastRadiusPreset.ValueString() != "DETAILED" {
+		t.Errorf("blast_radius_preset = %q, want DETAILED", m.BlastRadiusPreset.ValueString())
+	}
+	if got := m.BlastRadius.Attributes()["change_analysis_target_duration"].String(); got != `"30m0s"` {
+		t.Errorf("change_analysis_target_duration = %s", got)
+	}
+}
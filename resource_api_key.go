@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// knownAPIKeyScopes are the scopes that the Overmind API accepts for API keys.
+var knownAPIKeyScopes = []string{ //nolint:gochecknoglobals // constant list
+	"account:read",
+	"account:write",
+	"api:read",
+	"api:write",
+	"changes:read",
+	"changes:write",
+	"config:read",
+	"config:write",
+	"explore:read",
+	"gateway:stream",
+	"request:receive",
+	"reverselink:request",
+	"source:read",
+	"source:write",
+}
+
+var (
+	_ resource.Resource                   = (*apiKeyResource)(nil)
+	_ resource.ResourceWithImportState    = (*apiKeyResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*apiKeyResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*apiKeyResource)(nil)
+)
+
+type apiKeyResource struct {
+	apiKeys  sdpconnect.ApiKeyServiceClient
+	instance sdp.OvermindInstance
+}
+
+type apiKeyResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	Scopes          types.Set    `tfsdk:"scopes"`
+	RotationTrigger types.String `tfsdk:"rotation_trigger"`
+	Status          types.String `tfsdk:"status"`
+	Error           types.String `tfsdk:"error"`
+	Created         types.String `tfsdk:"created"`
+	LastUsed        types.String `tfsdk:"last_used"`
+	AuthorizeURL    types.String `tfsdk:"authorize_url"`
+	Key             types.String `tfsdk:"key"`
+}
+
+func NewAPIKeyResource() resource.Resource {
+	return &apiKeyResource{}
+}
+
+func (r *apiKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_key"
+}
+
+func (r *apiKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an Overmind API key. A new key must be authorized once by visiting authorize_url " +
+			"before it can be used; until then its status is KEY_STATUS_UNAUTHORIZED.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "API key UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Human-readable name for this key.",
+				Required:    true,
+			},
+			"scopes": schema.SetAttribute{
+				Description: "Scopes granted to the key, e.g. \"changes:write\". Changing this forces a new key.",
+				Required:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"rotation_trigger": schema.StringAttribute{
+				Description: "Arbitrary value that rotates the key in place when changed, e.g. a date or a time_rotating id. " +
+					"The rotated key must be authorized again.",
+				Optional: true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Key status, e.g. KEY_STATUS_READY. A key that has been revoked outside of Terraform " +
+					"is planned for recreation.",
+				Computed: true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported while authorizing the key, if status is KEY_STATUS_ERROR.",
+				Computed:    true,
+			},
+			"created": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the key was created.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_used": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the key was last used, or null if it never has been.",
+				Computed:    true,
+			},
+			"authorize_url": schema.StringAttribute{
+				Description: "URL to visit to authorize the key after it is created or rotated.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Description: "The API key itself.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *apiKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config apiKeyResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Scopes.IsNull() || config.Scopes.IsUnknown() {
+		return
+	}
+
+	var scopes []types.String
+	resp.Diagnostics.Append(config.Scopes.ElementsAs(ctx, &scopes, false)...)
+	for _, scope := range scopes {
+		if scope.IsUnknown() || slices.Contains(knownAPIKeyScopes, scope.ValueString()) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(path.Root("scopes"), "Invalid API key scope",
+			fmt.Sprintf("%q is not a known scope. Valid scopes are %s.", scope.ValueString(), strings.Join(knownAPIKeyScopes, ", ")))
+	}
+}
+
+// ModifyPlan replaces keys that have been revoked, and marks everything
+// derived from the key as unknown when rotation_trigger changes, since
+// rotating issues a new key.
+func (r *apiKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan apiKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Status.ValueString() == sdp.KeyStatus_KEY_STATUS_REVOKED.String() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("status"))
+		return
+	}
+
+	if !plan.RotationTrigger.Equal(state.RotationTrigger) {
+		for _, attr := range []string{"id", "status", "error", "created", "last_used", "authorize_url", "key"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+		}
+	}
+}
+
+func (r *apiKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.apiKeys = clients.APIKeys
+	r.instance = clients.Instance
+}
+
+func (r *apiKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKey Create")
+	defer span.End()
+
+	var plan apiKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.apiKey.name", plan.Name.ValueString()))
+
+	var scopes []string
+	resp.Diagnostics.Append(plan.Scopes.ElementsAs(ctx, &scopes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createResp, err := r.apiKeys.CreateAPIKey(ctx, connect.NewRequest(&sdp.CreateAPIKeyRequest{
+		Name:                  plan.Name.ValueString(),
+		Scopes:                scopes,
+		FinalFrontendRedirect: finalFrontendRedirect(r.instance),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateAPIKey failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setCreateResponse(ctx, createResp.Msg)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.String("ovm.apiKey.id", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(plan.authorizeWarning()...)
+}
+
+func (r *apiKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKey Read")
+	defer span.End()
+
+	var state apiKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.apiKey.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid API key ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	getResp, err := r.apiKeys.GetAPIKey(ctx, connect.NewRequest(&sdp.GetAPIKeyRequest{
+		Uuid: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.apiKey.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAPIKey failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setAPIKey(ctx, getResp.Msg.GetKey())...)
+	span.SetAttributes(attribute.String("ovm.apiKey.status", state.Status.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *apiKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKey Update")
+	defer span.End()
+
+	var plan apiKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state apiKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.apiKey.id", state.ID.ValueString()),
+		attribute.String("ovm.apiKey.name", plan.Name.ValueString()),
+	)
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid API key ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	updateResp, err := r.apiKeys.UpdateAPIKey(ctx, connect.NewRequest(&sdp.UpdateAPIKeyRequest{
+		Uuid: uuidBytes,
+		Properties: &sdp.APIKeyProperties{
+			Name: plan.Name.ValueString(),
+		},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateAPIKey failed")
+		return
+	}
+
+	if plan.RotationTrigger.Equal(state.RotationTrigger) {
+		plan.AuthorizeURL = state.AuthorizeURL
+		plan.Key = state.Key
+		resp.Diagnostics.Append(plan.setAPIKey(ctx, updateResp.Msg.GetKey())...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	span.SetAttributes(attribute.Bool("ovm.apiKey.rotated", true))
+
+	refreshResp, err := r.apiKeys.RefreshAPIKey(ctx, connect.NewRequest(&sdp.RefreshAPIKeyRequest{
+		Uuid:                  uuidBytes,
+		FinalFrontendRedirect: finalFrontendRedirect(r.instance),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to rotate API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "RefreshAPIKey failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setCreateResponse(ctx, refreshResp.Msg.GetResponse())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(plan.authorizeWarning()...)
+}
+
+func (r *apiKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKey Delete")
+	defer span.End()
+
+	var state apiKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.apiKey.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid API key ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.apiKeys.DeleteAPIKey(ctx, connect.NewRequest(&sdp.DeleteAPIKeyRequest{
+		Uuid: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.apiKey.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteAPIKey failed")
+	}
+}
+
+func (r *apiKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "APIKey Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.apiKey.id", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// finalFrontendRedirect is where the browser lands after authorizing a key.
+func finalFrontendRedirect(oi sdp.OvermindInstance) string {
+	if oi.FrontendUrl == nil {
+		return ""
+	}
+	return oi.FrontendUrl.String()
+}
+
+// setCreateResponse records a newly created or rotated key. Rotation issues a
+// key with a new UUID, so the ID is always taken from the response.
+func (m *apiKeyResourceModel) setCreateResponse(ctx context.Context, msg *sdp.CreateAPIKeyResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keyUUID, err := uuid.FromBytes(msg.GetKey().GetMetadata().GetUuid())
+	if err != nil {
+		diags.AddError("Failed to parse API key UUID", err.Error())
+		return diags
+	}
+	m.ID = types.StringValue(keyUUID.String())
+	m.AuthorizeURL = stringOrNull(msg.GetAuthorizeURL())
+	m.Key = types.StringNull()
+
+	diags.Append(m.setAPIKey(ctx, msg.GetKey())...)
+	return diags
+}
+
+// setAPIKey copies the API representation of the key into the model. The
+// secret is only returned by some calls, so it is kept from state otherwise.
+func (m *apiKeyResourceModel) setAPIKey(ctx context.Context, key *sdp.APIKey) diag.Diagnostics {
+	md := key.GetMetadata()
+
+	m.Name = types.StringValue(key.GetProperties().GetName())
+	m.Status = types.StringValue(md.GetStatus().String())
+	m.Error = stringOrNull(md.GetError())
+	m.Created = timestampString(md.GetCreated())
+	m.LastUsed = timestampString(md.GetLastUsed())
+	if md.GetKey() != "" {
+		m.Key = types.StringValue(md.GetKey())
+	}
+	if m.AuthorizeURL.IsUnknown() {
+		m.AuthorizeURL = types.StringNull()
+	}
+	if m.Key.IsUnknown() {
+		m.Key = types.StringNull()
+	}
+
+	scopes, diags := types.SetValueFrom(ctx, types.StringType, nonNilStrings(md.GetScopes()))
+	m.Scopes = scopes
+	return diags
+}
+
+// authorizeWarning reminds the user to authorize a key that cannot be used
+// yet.
+func (m *apiKeyResourceModel) authorizeWarning() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if m.Status.ValueString() == sdp.KeyStatus_KEY_STATUS_UNAUTHORIZED.String() && !m.AuthorizeURL.IsNull() {
+		diags.AddWarning("API key must be authorized",
+			fmt.Sprintf("API key %q cannot be used until it has been authorized at %s", m.Name.ValueString(), m.AuthorizeURL.ValueString()))
+	}
+	return diags
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- mock ApiKeyService handler ---
+
+type mockAPIKeyHandler struct {
+	sdpconnect.UnimplementedApiKeyServiceHandler
+	mu   sync.Mutex
+	keys map[string]*sdp.APIKey
+}
+
+func newMockAPIKeyHandler() *mockAPIKeyHandler {
+	return &mockAPIKeyHandler{keys: make(map[string]*sdp.APIKey)}
+}
+
+func (m *mockAPIKeyHandler) newKey(name string, scopes []string) *sdp.CreateAPIKeyResponse {
+	id := uuid.New()
+	key := &sdp.APIKey{
+		Metadata: &sdp.APIKeyMetadata{
+			Uuid:    id[:],
+			Created: timestamppb.Now(),
+			Key:     "ovm_api_" + id.String(),
+			Scopes:  scopes,
+			Status:  sdp.KeyStatus_KEY_STATUS_UNAUTHORIZED,
+		},
+		Properties: &sdp.APIKeyProperties{Name: name},
+	}
+	m.keys[id.String()] = key
+	return &sdp.CreateAPIKeyResponse{
+		Key:          key,
+		AuthorizeURL: "https://auth.example.com/authorize?key=" + id.String(),
+	}
+}
+
+func (m *mockAPIKeyHandler) lookup(b []byte) (*sdp.APIKey, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	key, ok := m.keys[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return key, nil
+}
+
+func (m *mockAPIKeyHandler) CreateAPIKey(_ context.Context, req *connect.Request[sdp.CreateAPIKeyRequest]) (*connect.Response[sdp.CreateAPIKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return connect.NewResponse(m.newKey(req.Msg.GetName(), req.Msg.GetScopes())), nil
+}
+
+func (m *mockAPIKeyHandler) RefreshAPIKey(_ context.Context, req *connect.Request[sdp.RefreshAPIKeyRequest]) (*connect.Response[sdp.RefreshAPIKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, err := m.lookup(req.Msg.GetUuid())
+	if err != nil {
+		return nil, err
+	}
+	delete(m.keys, uuid.UUID(old.GetMetadata().GetUuid()).String())
+	return connect.NewResponse(&sdp.RefreshAPIKeyResponse{
+		Response: m.newKey(old.GetProperties().GetName(), old.GetMetadata().GetScopes()),
+	}), nil
+}
+
+func (m *mockAPIKeyHandler) GetAPIKey(_ context.Context, req *connect.Request[sdp.GetAPIKeyRequest]) (*connect.Response[sdp.GetAPIKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.lookup(req.Msg.GetUuid())
+	if err != nil {
+		return nil, err
+	}
+	// The secret is only returned when the key is created.
+	key = proto.Clone(key).(*sdp.APIKey)
+	key.Metadata.Key = ""
+	return connect.NewResponse(&sdp.GetAPIKeyResponse{Key: key}), nil
+}
+
+func (m *mockAPIKeyHandler) UpdateAPIKey(_ context.Context, req *connect.Request[sdp.UpdateAPIKeyRequest]) (*connect.Response[sdp.UpdateAPIKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.lookup(req.Msg.GetUuid())
+	if err != nil {
+		return nil, err
+	}
+	key.Properties = req.Msg.GetProperties()
+	return connect.NewResponse(&sdp.UpdateAPIKeyResponse{Key: key}), nil
+}
+
+func (m *mockAPIKeyHandler) ListAPIKeys(_ context.Context, _ *connect.Request[sdp.ListAPIKeysRequest]) (*connect.Response[sdp.ListAPIKeysResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]*sdp.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	return connect.NewResponse(&sdp.ListAPIKeysResponse{Keys: keys}), nil
+}
+
+func (m *mockAPIKeyHandler) DeleteAPIKey(_ context.Context, req *connect.Request[sdp.DeleteAPIKeyRequest]) (*connect.Response[sdp.DeleteAPIKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.lookup(req.Msg.GetUuid())
+	if err != nil {
+		return nil, err
+	}
+	delete(m.keys, uuid.UUID(key.GetMetadata().GetUuid()).String())
+	return connect.NewResponse(&sdp.DeleteAPIKeyResponse{}), nil
+}
+
+// setStatus simulates a key changing status outside of Terraform, e.g. being
+// authorized or revoked.
+func (m *mockAPIKeyHandler) setStatus(status sdp.KeyStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		key.Metadata.Status = status
+	}
+}
+
+// --- tests ---
+
+func testAccAPIKeyConfig(name, rotation string) string {
+	return `resource "overmind_api_key" "test" {
+  name             = "` + name + `"
+  scopes           = ["changes:write", "explore:read"]
+  rotation_trigger = "` + rotation + `"
+}`
+}
+
+func TestAPIKeyResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	var firstID, rotatedID string
+	captureID := func(dst *string) tfresource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			*dst = s.RootModule().Resources["overmind_api_key.test"].Primary.ID
+			return nil
+		}
+	}
+	idChanged := func(prev *string) tfresource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			if id := s.RootModule().Resources["overmind_api_key.test"].Primary.ID; id == *prev {
+				return fmt.Errorf("expected a new key, still %s", id)
+			}
+			return nil
+		}
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccAPIKeyConfig("ci", "2026-01"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_api_key.test", "id"),
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "scopes.#", "2"),
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "status", "KEY_STATUS_UNAUTHORIZED"),
+					tfresource.TestCheckResourceAttrSet("overmind_api_key.test", "authorize_url"),
+					tfresource.TestMatchResourceAttr("overmind_api_key.test", "key", regexp.MustCompile(`^ovm_api_`)),
+					tfresource.TestCheckNoResourceAttr("overmind_api_key.test", "last_used"),
+					captureID(&firstID),
+				),
+			},
+			{
+				PreConfig: func() { mocks.apiKeys.setStatus(sdp.KeyStatus_KEY_STATUS_READY) },
+				Config:    testAccAPIKeyConfig("ci-renamed", "2026-01"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "name", "ci-renamed"),
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "status", "KEY_STATUS_READY"),
+					tfresource.TestCheckResourceAttrPtr("overmind_api_key.test", "id", &firstID),
+					tfresource.TestMatchResourceAttr("overmind_api_key.test", "key", regexp.MustCompile(`^ovm_api_`)),
+				),
+			},
+			{
+				Config: testAccAPIKeyConfig("ci-renamed", "2026-02"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					idChanged(&firstID),
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "status", "KEY_STATUS_UNAUTHORIZED"),
+					captureID(&rotatedID),
+				),
+			},
+			{
+				PreConfig: func() { mocks.apiKeys.setStatus(sdp.KeyStatus_KEY_STATUS_REVOKED) },
+				Config:    testAccAPIKeyConfig("ci-renamed", "2026-02"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					idChanged(&rotatedID),
+					tfresource.TestCheckResourceAttr("overmind_api_key.test", "status", "KEY_STATUS_UNAUTHORIZED"),
+				),
+			},
+		},
+	})
+}
+
+func TestAPIKeyResource_InvalidScope(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_api_key" "test" {
+  name   = "ci"
+  scopes = ["changes:write", "everything:admin"]
+}`,
+				ExpectError: regexp.MustCompile(`Invalid API key scope`),
+			},
+		},
+	})
+}
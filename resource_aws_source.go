@@ -3,6 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
@@ -10,8 +13,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
 	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
@@ -21,9 +27,41 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+const awsSourceType = "aws"
+
+// AWS access strategies understood by the AWS source, stored in the
+// "aws-access-strategy" config key.
+const (
+	awsAccessStrategyExternalID = "external-id"
+	awsAccessStrategyAccessKey  = "access-key"
+	awsAccessStrategySSOProfile = "sso-profile"
+	awsAccessStrategyDefaults   = "defaults"
+)
+
+var awsAccessStrategies = []string{
+	awsAccessStrategyExternalID,
+	awsAccessStrategyAccessKey,
+	awsAccessStrategySSOProfile,
+	awsAccessStrategyDefaults,
+}
+
+// awsOwnedConfigKeys lists every config key that overmind_aws_source manages.
+// Any other key on the source is preserved on update.
+var awsOwnedConfigKeys = []string{
+	"aws-access-strategy",
+	"aws-regions",
+	"aws-external-id",
+	"aws-target-role-arn",
+	"aws-access-key-id",
+	"aws-secret-access-key",
+	"aws-profile",
+}
+
 var (
-	_ resource.Resource                = (*awsSourceResource)(nil)
-	_ resource.ResourceWithImportState = (*awsSourceResource)(nil)
+	_ resource.Resource                   = (*awsSourceResource)(nil)
+	_ resource.ResourceWithIdentity       = (*awsSourceResource)(nil)
+	_ resource.ResourceWithImportState    = (*awsSourceResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*awsSourceResource)(nil)
 )
 
 type awsSourceResource struct {
@@ -31,11 +69,27 @@ type awsSourceResource struct {
 }
 
 type awsSourceResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	Name       types.String `tfsdk:"name"`
-	AWSRoleARN types.String `tfsdk:"aws_role_arn"`
-	AWSRegions types.List   `tfsdk:"aws_regions"`
-	ExternalID types.String `tfsdk:"external_id"`
+	ID             types.String        `tfsdk:"id"`
+	Name           types.String        `tfsdk:"name"`
+	AccessStrategy types.String        `tfsdk:"access_strategy"`
+	AWSRoleARN     types.String        `tfsdk:"aws_role_arn"`
+	AWSRegions     types.List          `tfsdk:"aws_regions"`
+	ExternalID     types.String        `tfsdk:"external_id"`
+	WaitForHealthy types.Bool          `tfsdk:"wait_for_healthy"`
+	AccessKey      *awsAccessKeyModel  `tfsdk:"access_key"`
+	SSOProfile     *awsSSOProfileModel `tfsdk:"sso_profile"`
+	Health         types.Object        `tfsdk:"health"`
+	Timeouts       *timeoutsModel      `tfsdk:"timeouts"`
+}
+
+type awsAccessKeyModel struct {
+	AccessKeyID              types.String `tfsdk:"access_key_id"`
+	SecretAccessKeyWO        types.String `tfsdk:"secret_access_key_wo"`
+	SecretAccessKeyWOVersion types.Int64  `tfsdk:"secret_access_key_wo_version"`
+}
+
+type awsSSOProfileModel struct {
+	Profile types.String `tfsdk:"profile"`
 }
 
 func NewAWSSourceResource() resource.Resource {
@@ -46,6 +100,10 @@ func (r *awsSourceResource) Metadata(_ context.Context, req resource.MetadataReq
 	resp.TypeName = req.ProviderTypeName + "_aws_source"
 }
 
+func (r *awsSourceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = sourceIdentitySchema()
+}
+
 func (r *awsSourceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages an Overmind AWS infrastructure source.",
@@ -61,9 +119,19 @@ func (r *awsSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "Human-readable name for this source.",
 				Required:    true,
 			},
+			"access_strategy": schema.StringAttribute{
+				Description: "How the source authenticates to AWS. One of \"external-id\" (assume aws_role_arn " +
+					"using the Overmind external ID), \"access-key\" (static credentials from the access_key block), " +
+					"\"sso-profile\" (a named profile from the sso_profile block) or \"defaults\" (the AWS SDK " +
+					"default credential chain, for self-hosted sources). Defaults to \"external-id\".",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(awsAccessStrategyExternalID),
+			},
 			"aws_role_arn": schema.StringAttribute{
-				Description: "ARN of the IAM role to assume in the customer's AWS account.",
-				Required:    true,
+				Description: "ARN of the IAM role to assume in the customer's AWS account. " +
+					"Required when access_strategy is \"external-id\".",
+				Optional: true,
 			},
 			"aws_regions": schema.ListAttribute{
 				Description: "AWS regions this source should discover resources in.",
@@ -77,7 +145,116 @@ func (r *awsSourceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"wait_for_healthy": schema.BoolAttribute{
+				Description: "Wait for the source to report a healthy status after create and update, " +
+					"failing the apply with the source's error if it does not. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"health": sourceHealthSchemaAttribute(),
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+			"access_key": schema.SingleNestedBlock{
+				Description: "Static AWS credentials. Only valid when access_strategy is \"access-key\".",
+				Attributes: map[string]schema.Attribute{
+					"access_key_id": schema.StringAttribute{
+						Description: "AWS access key ID.",
+						Optional:    true,
+					},
+					"secret_access_key_wo": schema.StringAttribute{
+						Description: "AWS secret access key. This value is write-only and is never stored in " +
+							"state; change secret_access_key_wo_version to send a new value.",
+						Optional:  true,
+						Sensitive: true,
+						WriteOnly: true,
+					},
+					"secret_access_key_wo_version": schema.Int64Attribute{
+						Description: "Arbitrary version number for secret_access_key_wo. " +
+							"Increment it to update the stored secret.",
+						Optional: true,
+					},
+				},
+			},
+			"sso_profile": schema.SingleNestedBlock{
+				Description: "AWS SSO profile. Only valid when access_strategy is \"sso-profile\".",
+				Attributes: map[string]schema.Attribute{
+					"profile": schema.StringAttribute{
+						Description: "Name of the AWS profile to load credentials from.",
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *awsSourceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config awsSourceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(config.Timeouts.validate()...)
+
+	if config.AccessStrategy.IsUnknown() {
+		return
+	}
+	strategy := config.AccessStrategy.ValueString()
+	if config.AccessStrategy.IsNull() {
+		strategy = awsAccessStrategyExternalID
+	}
+	if !slices.Contains(awsAccessStrategies, strategy) {
+		resp.Diagnostics.AddAttributeError(path.Root("access_strategy"), "Invalid access strategy",
+			fmt.Sprintf("access_strategy must be one of %q, got %q.", awsAccessStrategies, strategy))
+		return
+	}
+
+	// Each strategy owns a distinct set of fields; anything else set in the
+	// configuration would be silently ignored by the source, so reject it.
+	requireSet := func(p path.Path, set bool) {
+		if !set {
+			resp.Diagnostics.AddAttributeError(p, "Missing access strategy configuration",
+				fmt.Sprintf("%s must be set when access_strategy is %q.", p, strategy))
+		}
+	}
+	forbidSet := func(p path.Path, set bool) {
+		if set {
+			resp.Diagnostics.AddAttributeError(p, "Conflicting access strategy configuration",
+				fmt.Sprintf("%s can only be set for the matching access_strategy, but access_strategy is %q.", p, strategy))
+		}
+	}
+
+	roleARNSet := !config.AWSRoleARN.IsNull()
+	accessKeySet := config.AccessKey != nil
+	ssoProfileSet := config.SSOProfile != nil
+
+	switch strategy {
+	case awsAccessStrategyExternalID:
+		requireSet(path.Root("aws_role_arn"), roleARNSet)
+		forbidSet(path.Root("access_key"), accessKeySet)
+		forbidSet(path.Root("sso_profile"), ssoProfileSet)
+	case awsAccessStrategyAccessKey:
+		forbidSet(path.Root("aws_role_arn"), roleARNSet)
+		requireSet(path.Root("access_key"), accessKeySet)
+		forbidSet(path.Root("sso_profile"), ssoProfileSet)
+		if accessKeySet {
+			requireSet(path.Root("access_key").AtName("access_key_id"), !config.AccessKey.AccessKeyID.IsNull())
+			requireSet(path.Root("access_key").AtName("secret_access_key_wo"), !config.AccessKey.SecretAccessKeyWO.IsNull())
+		}
+	case awsAccessStrategySSOProfile:
+		forbidSet(path.Root("aws_role_arn"), roleARNSet)
+		forbidSet(path.Root("access_key"), accessKeySet)
+		requireSet(path.Root("sso_profile"), ssoProfileSet)
+		if ssoProfileSet {
+			requireSet(path.Root("sso_profile").AtName("profile"), !config.SSOProfile.Profile.IsNull())
+		}
+	case awsAccessStrategyDefaults:
+		forbidSet(path.Root("aws_role_arn"), roleARNSet)
+		forbidSet(path.Root("access_key"), accessKeySet)
+		forbidSet(path.Root("sso_profile"), ssoProfileSet)
 	}
 }
 
@@ -85,13 +262,13 @@ func (r *awsSourceResource) Configure(_ context.Context, req resource.ConfigureR
 	if req.ProviderData == nil {
 		return
 	}
-	mgmt, ok := req.ProviderData.(sdpconnect.ManagementServiceClient)
+	clients, ok := req.ProviderData.(*overmindClients)
 	if !ok {
 		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected sdpconnect.ManagementServiceClient, got %T", req.ProviderData))
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
 		return
 	}
-	r.mgmt = mgmt
+	r.mgmt = clients.Management
 }
 
 func (r *awsSourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -119,18 +296,13 @@ func (r *awsSourceResource) Create(ctx context.Context, req resource.CreateReque
 	}
 	externalID := extIDResp.Msg.GetAwsExternalId()
 
-	regions, diags := regionsFromList(ctx, plan.AWSRegions)
+	sourceConfig, diags := plan.sourceConfig(ctx, req.Config, externalID)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	sourceConfig, err := structpb.NewStruct(map[string]any{
-		"aws-access-strategy": "external-id",
-		"aws-external-id":     externalID,
-		"aws-target-role-arn": plan.AWSRoleARN.ValueString(),
-		"aws-regions":         toAnySlice(regions),
-	})
+	sourceConfigStruct, err := structpb.NewStruct(sourceConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to build source config", err.Error())
 		span.RecordError(err)
@@ -141,8 +313,8 @@ func (r *awsSourceResource) Create(ctx context.Context, req resource.CreateReque
 	createResp, err := r.mgmt.CreateSource(ctx, connect.NewRequest(&sdp.CreateSourceRequest{
 		Properties: &sdp.SourceProperties{
 			DescriptiveName: plan.Name.ValueString(),
-			Type:            "aws",
-			Config:          sourceConfig,
+			Type:            awsSourceType,
+			Config:          sourceConfigStruct,
 		},
 	}))
 	if err != nil {
@@ -162,11 +334,26 @@ func (r *awsSourceResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	plan.ID = types.StringValue(sourceUUID.String())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: plan.ID})...)
 	plan.ExternalID = types.StringValue(externalID)
 
 	span.SetAttributes(attribute.String("ovm.source.id", sourceUUID.String()))
 
+	plan.Health = types.ObjectNull(sourceHealthAttrTypes)
+
+	// Save the source before waiting so that a source that never becomes
+	// healthy is tainted rather than leaked.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.CreateTimeout(defaultSourceHealthTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.updateHealth(ctx, &plan, source.GetMetadata().GetUUID(), time.Time{}, timeout, &resp.State)...)
 }
 
 func (r *awsSourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -204,28 +391,32 @@ func (r *awsSourceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	source := getResp.Msg.GetSource()
-	props := source.GetProperties()
+	resp.Diagnostics.Append(state.setSourceProperties(ctx, getResp.Msg.GetSource().GetProperties())...)
 
-	state.Name = types.StringValue(props.GetDescriptiveName())
+	health, diags := readSourceHealth(ctx, r.mgmt, uuidBytes)
+	resp.Diagnostics.Append(diags...)
+	state.Health = health
 
-	if cfg := props.GetConfig(); cfg != nil {
-		fields := cfg.GetFields()
-		if v, ok := fields["aws-target-role-arn"]; ok {
-			state.AWSRoleARN = types.StringValue(v.GetStringValue())
-		}
-		if v, ok := fields["aws-regions"]; ok {
-			regionVals := regionsFromStructValue(v)
-			listVal, diags := types.ListValueFrom(ctx, types.StringType, regionVals)
-			resp.Diagnostics.Append(diags...)
-			state.AWSRegions = listVal
-		}
-		if v, ok := fields["aws-external-id"]; ok {
-			state.ExternalID = types.StringValue(v.GetStringValue())
+	if state.WaitForHealthy.IsNull() {
+		state.WaitForHealthy = types.BoolValue(true)
+	}
+
+	// The external ID is stable per account and is exposed regardless of the
+	// access strategy, so fill it in for imported non-external-id sources.
+	if state.ExternalID.IsNull() || state.ExternalID.IsUnknown() {
+		extIDResp, err := r.mgmt.GetOrCreateAWSExternalId(ctx,
+			connect.NewRequest(&sdp.GetOrCreateAWSExternalIdRequest{}))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get AWS external ID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetOrCreateAWSExternalId failed")
+			return
 		}
+		state.ExternalID = types.StringValue(extIDResp.Msg.GetAwsExternalId())
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: state.ID})...)
 }
 
 func (r *awsSourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -257,20 +448,26 @@ func (r *awsSourceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	regions, diags := regionsFromList(ctx, plan.AWSRegions)
+	sourceConfig, diags := plan.sourceConfig(ctx, req.Config, state.ExternalID.ValueString())
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	externalID := state.ExternalID.ValueString()
+	// Read the current source first so that config keys and additional
+	// config written by the UI or the backend survive the update.
+	getResp, err := r.mgmt.GetSource(ctx, connect.NewRequest(&sdp.GetSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSource failed")
+		return
+	}
+	current := getResp.Msg.GetSource().GetProperties()
 
-	sourceConfig, err := structpb.NewStruct(map[string]any{
-		"aws-access-strategy": "external-id",
-		"aws-external-id":     externalID,
-		"aws-target-role-arn": plan.AWSRoleARN.ValueString(),
-		"aws-regions":         toAnySlice(regions),
-	})
+	sourceConfigStruct, unmanaged, err := mergeSourceConfig(current.GetConfig(), awsOwnedConfigKeys, sourceConfig)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to build source config", err.Error())
 		span.RecordError(err)
@@ -278,12 +475,33 @@ func (r *awsSourceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	if len(unmanaged) > 0 {
+		span.SetAttributes(attribute.StringSlice("ovm.source.unmanagedKeys", unmanaged))
+		resp.Diagnostics.AddWarning("Source has unmanaged config keys",
+			fmt.Sprintf("Source %s has config keys that are not managed by this resource and were left unchanged: %s. "+
+				"These were most likely set in the Overmind UI.", state.ID.ValueString(), strings.Join(unmanaged, ", ")))
+	}
+
+	// Note the source's last heartbeat before sending the new config, so that
+	// statuses reported for the old config are not taken for the new one's.
+	var lastHeartbeat time.Time
+	if plan.WaitForHealthy.ValueBool() {
+		lastHeartbeat, err = lastSourceHeartbeat(ctx, r.mgmt, uuidBytes)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read source health", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetSourceStatus failed")
+			return
+		}
+	}
+
 	_, err = r.mgmt.UpdateSource(ctx, connect.NewRequest(&sdp.UpdateSourceRequest{
 		UUID: uuidBytes,
 		Properties: &sdp.SourceProperties{
-			DescriptiveName: plan.Name.ValueString(),
-			Type:            "aws",
-			Config:          sourceConfig,
+			DescriptiveName:  plan.Name.ValueString(),
+			Type:             awsSourceType,
+			Config:           sourceConfigStruct,
+			AdditionalConfig: current.GetAdditionalConfig(),
 		},
 	}))
 	if err != nil {
@@ -296,7 +514,12 @@ func (r *awsSourceResource) Update(ctx context.Context, req resource.UpdateReque
 	plan.ID = state.ID
 	plan.ExternalID = state.ExternalID
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	timeout, diags := plan.Timeouts.UpdateTimeout(defaultSourceHealthTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.updateHealth(ctx, &plan, uuidBytes, lastHeartbeat, timeout, &resp.State)...)
 }
 
 func (r *awsSourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -337,9 +560,142 @@ func (r *awsSourceResource) ImportState(ctx context.Context, req resource.Import
 	ctx, span := tracing.Tracer().Start(ctx, "AWSSource Import")
 	defer span.End()
 
-	span.SetAttributes(attribute.String("ovm.source.id", req.ID))
+	importSourceOfType(ctx, r.mgmt, awsSourceType, req, resp)
+}
+
+// updateHealth records the source's health in state after create or update.
+// With wait_for_healthy it first waits for the source to become healthy and
+// fails with the source's own error text if it does not. After an update,
+// lastHeartbeat is the heartbeat from before the new config was sent, so
+// statuses up to it are not trusted.
+func (r *awsSourceResource) updateHealth(ctx context.Context, model *awsSourceResourceModel, sourceUUID []byte, lastHeartbeat time.Time, timeout time.Duration, state *tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !model.WaitForHealthy.ValueBool() {
+		health, d := readSourceHealth(ctx, r.mgmt, sourceUUID)
+		diags.Append(d...)
+		model.Health = health
+		diags.Append(state.Set(ctx, model)...)
+		return diags
+	}
+
+	health, err := waitForSourceHealthy(ctx, r.mgmt, sourceUUID, lastHeartbeat, timeout)
+	healthObj, d := sourceHealthObject(ctx, health)
+	diags.Append(d...)
+	model.Health = healthObj
+	diags.Append(state.Set(ctx, model)...)
+	if err != nil {
+		diags.AddError("Source did not become healthy", err.Error())
+	}
+	return diags
+}
+
+// setSourceProperties copies the API representation of the source into the
+// model. Fields that only exist in Terraform, such as the secret's version,
+// wait_for_healthy and timeouts, are left as they are.
+func (m *awsSourceResourceModel) setSourceProperties(ctx context.Context, props *sdp.SourceProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.Name = types.StringValue(props.GetDescriptiveName())
+
+	if cfg := props.GetConfig(); cfg != nil {
+		fields := cfg.GetFields()
+
+		strategy := awsAccessStrategyExternalID
+		if v, ok := fields["aws-access-strategy"]; ok {
+			strategy = v.GetStringValue()
+		}
+		m.AccessStrategy = types.StringValue(strategy)
+
+		if v, ok := fields["aws-target-role-arn"]; ok && strategy == awsAccessStrategyExternalID {
+			m.AWSRoleARN = types.StringValue(v.GetStringValue())
+		} else {
+			m.AWSRoleARN = types.StringNull()
+		}
+		if v, ok := fields["aws-regions"]; ok {
+			regionVals := stringsFromStructValue(v)
+			listVal, d := types.ListValueFrom(ctx, types.StringType, regionVals)
+			diags.Append(d...)
+			m.AWSRegions = listVal
+		}
+		if v, ok := fields["aws-external-id"]; ok {
+			m.ExternalID = types.StringValue(v.GetStringValue())
+		}
+
+		// The secret is write-only and never read back; the version is kept
+		// from prior state so that it does not show up as drift.
+		if strategy == awsAccessStrategyAccessKey {
+			accessKey := &awsAccessKeyModel{
+				AccessKeyID:              types.StringValue(fields["aws-access-key-id"].GetStringValue()),
+				SecretAccessKeyWO:        types.StringNull(),
+				SecretAccessKeyWOVersion: types.Int64Null(),
+			}
+			if m.AccessKey != nil {
+				accessKey.SecretAccessKeyWOVersion = m.AccessKey.SecretAccessKeyWOVersion
+			}
+			m.AccessKey = accessKey
+		} else {
+			m.AccessKey = nil
+		}
+
+		if strategy == awsAccessStrategySSOProfile {
+			m.SSOProfile = &awsSSOProfileModel{
+				Profile: types.StringValue(fields["aws-profile"].GetStringValue()),
+			}
+		} else {
+			m.SSOProfile = nil
+		}
+	}
+
+	return diags
+}
+
+// sourceConfig builds the AWS source config keys for the planned access
+// strategy. The write-only secret access key is only available from the
+// configuration, never from the plan or state.
+func (m *awsSourceResourceModel) sourceConfig(ctx context.Context, config tfsdk.Config, externalID string) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	regions, d := stringsFromList(ctx, m.AWSRegions)
+	diags.Append(d...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	strategy := m.AccessStrategy.ValueString()
+	fields := map[string]any{
+		"aws-access-strategy": strategy,
+		"aws-regions":         toAnySlice(regions),
+	}
+
+	switch strategy {
+	case awsAccessStrategyExternalID:
+		fields["aws-external-id"] = externalID
+		fields["aws-target-role-arn"] = m.AWSRoleARN.ValueString()
+	case awsAccessStrategyAccessKey:
+		var secret types.String
+		diags.Append(config.GetAttribute(ctx, path.Root("access_key").AtName("secret_access_key_wo"), &secret)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		if secret.IsNull() || secret.IsUnknown() {
+			diags.AddAttributeError(path.Root("access_key").AtName("secret_access_key_wo"),
+				"Missing secret access key",
+				"secret_access_key_wo must be known at apply time. Write-only attributes require Terraform 1.11 or later.")
+			return nil, diags
+		}
+		if m.AccessKey != nil {
+			fields["aws-access-key-id"] = m.AccessKey.AccessKeyID.ValueString()
+		}
+		fields["aws-secret-access-key"] = secret.ValueString()
+	case awsAccessStrategySSOProfile:
+		if m.SSOProfile != nil {
+			fields["aws-profile"] = m.SSOProfile.Profile.ValueString()
+		}
+	case awsAccessStrategyDefaults:
+	}
 
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	return fields, diags
 }
 
 // --- helpers ---
@@ -353,10 +709,10 @@ func uuidToBytes(s string) ([]byte, error) {
 	return b, nil
 }
 
-func regionsFromList(ctx context.Context, list types.List) ([]string, diag.Diagnostics) {
-	var regions []string
-	diags := list.ElementsAs(ctx, &regions, false)
-	return regions, diags
+func stringsFromList(ctx context.Context, list types.List) ([]string, diag.Diagnostics) {
+	var out []string
+	diags := list.ElementsAs(ctx, &out, false)
+	return out, diags
 }
 
 func toAnySlice(ss []string) []any {
@@ -367,7 +723,7 @@ func toAnySlice(ss []string) []any {
 	return out
 }
 
-func regionsFromStructValue(v *structpb.Value) []string {
+func stringsFromStructValue(v *structpb.Value) []string {
 	lv := v.GetListValue()
 	if lv == nil {
 		return []string{}
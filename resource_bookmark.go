@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ resource.Resource                   = (*bookmarkResource)(nil)
+	_ resource.ResourceWithImportState    = (*bookmarkResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*bookmarkResource)(nil)
+)
+
+type bookmarkResource struct {
+	bookmarks sdpconnect.BookmarksServiceClient
+}
+
+type bookmarkResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Created     types.String `tfsdk:"created"`
+	Queries     []queryModel `tfsdk:"query"`
+}
+
+func NewBookmarkResource() resource.Resource {
+	return &bookmarkResource{}
+}
+
+func (r *bookmarkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bookmark"
+}
+
+func (r *bookmarkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an Overmind bookmark: a named set of queries that can be run again later from the Explore page.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Bookmark UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the bookmark.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the bookmark.",
+				Optional:    true,
+			},
+			"created": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the bookmark was created.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"query": queryBlock("Queries saved in the bookmark, in the order they are run."),
+		},
+	}
+}
+
+func (r *bookmarkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config bookmarkResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateQueries(config.Queries, path.Root("query"))...)
+}
+
+func (r *bookmarkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.bookmarks = clients.Bookmarks
+}
+
+func (r *bookmarkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmark Create")
+	defer span.End()
+
+	var plan bookmarkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.bookmark.name", plan.Name.ValueString()))
+
+	createResp, err := r.bookmarks.CreateBookmark(ctx, connect.NewRequest(&sdp.CreateBookmarkRequest{
+		Properties: plan.properties(),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create bookmark", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateBookmark failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setBookmark(createResp.Msg.GetBookmark())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.String("ovm.bookmark.id", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bookmarkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmark Read")
+	defer span.End()
+
+	var state bookmarkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.bookmark.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid bookmark ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	getResp, err := r.bookmarks.GetBookmark(ctx, connect.NewRequest(&sdp.GetBookmarkRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.bookmark.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read bookmark", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetBookmark failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setBookmark(getResp.Msg.GetBookmark())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bookmarkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmark Update")
+	defer span.End()
+
+	var plan bookmarkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state bookmarkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.bookmark.id", state.ID.ValueString()),
+		attribute.String("ovm.bookmark.name", plan.Name.ValueString()),
+	)
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid bookmark ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	updateResp, err := r.bookmarks.UpdateBookmark(ctx, connect.NewRequest(&sdp.UpdateBookmarkRequest{
+		UUID:       uuidBytes,
+		Properties: plan.properties(),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update bookmark", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateBookmark failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setBookmark(updateResp.Msg.GetBookmark())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bookmarkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmark Delete")
+	defer span.End()
+
+	var state bookmarkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.bookmark.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid bookmark ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.bookmarks.DeleteBookmark(ctx, connect.NewRequest(&sdp.DeleteBookmarkRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.bookmark.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete bookmark", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteBookmark failed")
+	}
+}
+
+func (r *bookmarkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Bookmark Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.bookmark.id", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (m *bookmarkResourceModel) properties() *sdp.BookmarkProperties {
+	return &sdp.BookmarkProperties{
+		Name:        m.Name.ValueString(),
+		Description: m.Description.ValueString(),
+		Queries:     queriesToSDP(m.Queries),
+	}
+}
+
+// setBookmark copies the API representation of the bookmark into the model.
+func (m *bookmarkResourceModel) setBookmark(bookmark *sdp.Bookmark) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	id, err := uuid.FromBytes(bookmark.GetMetadata().GetUUID())
+	if err != nil {
+		diags.AddError("Failed to parse bookmark UUID", err.Error())
+		return diags
+	}
+
+	props := bookmark.GetProperties()
+	m.ID = types.StringValue(id.String())
+	m.Name = types.StringValue(props.GetName())
+	m.Description = stringOrNull(props.GetDescription())
+	m.Created = timestampString(bookmark.GetMetadata().GetCreated())
+	m.Queries = nil
+	if len(props.GetQueries()) > 0 {
+		m.Queries = queryModelsFromSDP(props.GetQueries())
+	}
+	return diags
+}
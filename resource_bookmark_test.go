@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- mock BookmarksService handler ---
+
+type mockBookmarksHandler struct {
+	sdpconnect.UnimplementedBookmarksServiceHandler
+	mu        sync.Mutex
+	bookmarks map[string]*sdp.Bookmark
+}
+
+func newMockBookmarksHandler() *mockBookmarksHandler {
+	return &mockBookmarksHandler{bookmarks: make(map[string]*sdp.Bookmark)}
+}
+
+func (m *mockBookmarksHandler) lookup(b []byte) (*sdp.Bookmark, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	bookmark, ok := m.bookmarks[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return bookmark, nil
+}
+
+func (m *mockBookmarksHandler) CreateBookmark(_ context.Context, req *connect.Request[sdp.CreateBookmarkRequest]) (*connect.Response[sdp.CreateBookmarkResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New()
+	bookmark := &sdp.Bookmark{
+		Metadata:   &sdp.BookmarkMetadata{UUID: id[:], Created: timestamppb.Now()},
+		Properties: req.Msg.GetProperties(),
+	}
+	m.bookmarks[id.String()] = bookmark
+	return connect.NewResponse(&sdp.CreateBookmarkResponse{Bookmark: bookmark}), nil
+}
+
+func (m *mockBookmarksHandler) GetBookmark(_ context.Context, req *connect.Request[sdp.GetBookmarkRequest]) (*connect.Response[sdp.GetBookmarkResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bookmark, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&sdp.GetBookmarkResponse{Bookmark: bookmark}), nil
+}
+
+func (m *mockBookmarksHandler) UpdateBookmark(_ context.Context, req *connect.Request[sdp.UpdateBookmarkRequest]) (*connect.Response[sdp.UpdateBookmarkResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bookmark, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	bookmark.Properties = req.Msg.GetProperties()
+	return connect.NewResponse(&sdp.UpdateBookmarkResponse{Bookmark: bookmark}), nil
+}
+
+func (m *mockBookmarksHandler) DeleteBookmark(_ context.Context, req *connect.Request[sdp.DeleteBookmarkRequest]) (*connect.Response[sdp.DeleteBookmarkResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bookmark, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	delete(m.bookmarks, uuid.UUID(bookmark.GetMetadata().GetUUID()).String())
+	return connect.NewResponse(&sdp.DeleteBookmarkResponse{}), nil
+}
+
+func (m *mockBookmarksHandler) ListBookmarks(_ context.Context, _ *connect.Request[sdp.ListBookmarksRequest]) (*connect.Response[sdp.ListBookmarkResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bookmarks := make([]*sdp.Bookmark, 0, len(m.bookmarks))
+	for _, bookmark := range m.bookmarks {
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return connect.NewResponse(&sdp.ListBookmarkResponse{Bookmarks: bookmarks}), nil
+}
+
+// --- tests ---
+
+func testAccBookmarkConfig(description string) string {
+	return `resource "overmind_bookmark" "critical_path" {
+  name        = "prod critical path"
+  description = "` + description + `"
+
+  query {
+    type  = "elbv2-load-balancer"
+    query = "prod-alb"
+    scope = "123456789012.eu-west-1"
+
+    link_depth = 3
+  }
+
+  query {
+    type   = "rds-db-cluster"
+    method = "LIST"
+    scope  = "123456789012.eu-west-1"
+  }
+}`
+}
+
+func TestBookmarkResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if len(mocks.bookmarks.bookmarks) != 0 {
+				return fmt.Errorf("expected all bookmarks to be deleted, %d remain", len(mocks.bookmarks.bookmarks))
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccBookmarkConfig("Load balancer to database"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_bookmark.critical_path", "id"),
+					tfresource.TestCheckResourceAttr("overmind_bookmark.critical_path", "query.#", "2"),
+					tfresource.TestCheckResourceAttr("overmind_bookmark.critical_path", "query.0.method", "GET"),
+					tfresource.TestCheckResourceAttr("overmind_bookmark.critical_path", "query.0.link_depth", "3"),
+					tfresource.TestCheckResourceAttr("overmind_bookmark.critical_path", "query.1.method", "LIST"),
+					tfresource.TestCheckNoResourceAttr("overmind_bookmark.critical_path", "query.1.query"),
+				),
+			},
+			{
+				Config: testAccBookmarkConfig("Everything between the ALB and Aurora"),
+				Check:  tfresource.TestCheckResourceAttr("overmind_bookmark.critical_path", "description", "Everything between the ALB and Aurora"),
+			},
+			{
+				Config: testAccBookmarkConfig("Everything between the ALB and Aurora") + `
+
+data "overmind_bookmarks" "critical_path" {
+  name = overmind_bookmark.critical_path.name
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_bookmarks.critical_path", "bookmarks.#", "1"),
+					tfresource.TestCheckResourceAttrPair("data.overmind_bookmarks.critical_path", "ids.0", "overmind_bookmark.critical_path", "id"),
+					tfresource.TestCheckResourceAttr("data.overmind_bookmarks.critical_path", "bookmarks.0.queries.1.type", "rds-db-cluster"),
+				),
+			},
+			{
+				ResourceName:      "overmind_bookmark.critical_path",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestBookmarkResource_InvalidQuery(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_bookmark" "bad" {
+  name = "bad"
+
+  query {
+    type  = "ec2-instance"
+    scope = "*"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`Invalid query`),
+			},
+		},
+	})
+}
+
+func TestValidateQueries(t *testing.T) {
+	query := func(method, q string, linkDepth int64) queryModel {
+		return queryModel{
+			Type:        types.StringValue("ec2-instance"),
+			Method:      types.StringValue(method),
+			Query:       stringOrNull(q),
+			Scope:       types.StringValue("*"),
+			LinkDepth:   types.Int64Value(linkDepth),
+			IgnoreCache: types.BoolValue(false),
+		}
+	}
+
+	emptyQuery := query("LIST", "", 0)
+	emptyQuery.Query = types.StringValue("")
+
+	tests := []struct {
+		name    string
+		query   queryModel
+		summary string
+	}{
+		{name: "get", query: query("GET", "i-0123456789", 0)},
+		{name: "list without query", query: query("LIST", "", 2)},
+		{name: "get without query", query: query("GET", "", 0), summary: "Invalid query"},
+		{name: "unknown method", query: query("FIND", "x", 0), summary: "Invalid query method"},
+		{name: "negative link depth", query: query("SEARCH", "x", -1), summary: "Invalid link depth"},
+		{name: "empty query", query: emptyQuery, summary: "Empty query"},
+		{name: "unknown type", query: queryModel{
+			Type: types.StringUnknown(), Method: types.StringValue("GET"), Query: types.StringNull(),
+			Scope: types.StringValue("*"), LinkDepth: types.Int64Value(0), IgnoreCache: types.BoolValue(false),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateQueries([]queryModel{tt.query}, path.Root("query"))
+			if tt.summary == "" {
+				if diags.HasError() {
+					t.Errorf("unexpected error: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags[0].Summary() != tt.summary {
+				t.Errorf("expected %q, got %v", tt.summary, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ resource.Resource                = (*changeResource)(nil)
+	_ resource.ResourceWithImportState = (*changeResource)(nil)
+)
+
+type changeResource struct {
+	changes sdpconnect.ChangesServiceClient
+}
+
+type changeResourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Title                 types.String `tfsdk:"title"`
+	Description           types.String `tfsdk:"description"`
+	TicketLink            types.String `tfsdk:"ticket_link"`
+	Owner                 types.String `tfsdk:"owner"`
+	CcEmails              types.String `tfsdk:"cc_emails"`
+	Repo                  types.String `tfsdk:"repo"`
+	Tags                  types.Map    `tfsdk:"tags"`
+	Status                types.String `tfsdk:"status"`
+	Created               types.String `tfsdk:"created"`
+	BlastRadiusSnapshotID types.String `tfsdk:"blast_radius_snapshot_id"`
+	LowRiskCount          types.Int64  `tfsdk:"low_risk_count"`
+	MediumRiskCount       types.Int64  `tfsdk:"medium_risk_count"`
+	HighRiskCount         types.Int64  `tfsdk:"high_risk_count"`
+}
+
+func NewChangeResource() resource.Resource {
+	return &changeResource{}
+}
+
+func (r *changeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_change"
+}
+
+func (r *changeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Registers a change in Overmind, such as a release, so its blast radius and risks can be analysed. " +
+			"Destroying the resource deletes the change only while it is still being defined; changes that have " +
+			"started are kept in Overmind as a record and only removed from Terraform state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Change UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"title": schema.StringAttribute{
+				Description: "Short title of the change.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the change.",
+				Optional:    true,
+			},
+			"ticket_link": schema.StringAttribute{
+				Description: "Link to the ticket for the change. Changes can be looked up by this link later.",
+				Optional:    true,
+			},
+			"owner": schema.StringAttribute{
+				Description: "Owner of the change.",
+				Optional:    true,
+			},
+			"cc_emails": schema.StringAttribute{
+				Description: "Comma-separated list of emails to keep updated with the status of the change.",
+				Optional:    true,
+			},
+			"repo": schema.StringAttribute{
+				Description: "Repository the change comes from. Not necessarily a URL.",
+				Optional:    true,
+			},
+			"tags": schema.MapAttribute{
+				Description: "Tags set on the change. Tags that Overmind adds automatically are not included.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the change: CHANGE_STATUS_DEFINING, CHANGE_STATUS_HAPPENING, " +
+					"CHANGE_STATUS_PROCESSING or CHANGE_STATUS_DONE.",
+				Computed: true,
+			},
+			"created": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the change was created.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"blast_radius_snapshot_id": schema.StringAttribute{
+				Description: "UUID of the snapshot holding the change's blast radius, once it has been calculated. " +
+					"Read it with the overmind_snapshot data source.",
+				Computed: true,
+			},
+			"low_risk_count": schema.Int64Attribute{
+				Description: "Number of low severity risks found for the change.",
+				Computed:    true,
+			},
+			"medium_risk_count": schema.Int64Attribute{
+				Description: "Number of medium severity risks found for the change.",
+				Computed:    true,
+			},
+			"high_risk_count": schema.Int64Attribute{
+				Description: "Number of high severity risks found for the change.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *changeResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.changes = clients.Changes
+}
+
+func (r *changeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Change Create")
+	defer span.End()
+
+	var plan changeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.title", plan.Title.ValueString()))
+
+	props := &sdp.ChangeProperties{}
+	resp.Diagnostics.Append(plan.applyProperties(ctx, props)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createResp, err := r.changes.CreateChange(ctx, connect.NewRequest(&sdp.CreateChangeRequest{
+		Properties: props,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateChange failed")
+		return
+	}
+
+	change := createResp.Msg.GetChange()
+	resp.Diagnostics.Append(plan.setChange(change)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.String("ovm.change.id", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(r.readRisks(ctx, change.GetMetadata().GetUUID(), &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *changeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Change Read")
+	defer span.End()
+
+	var state changeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.id", state.ID.ValueString()))
+
+	change, err := r.getChange(ctx, state.ID.ValueString(), true)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.change.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetChange failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setChange(change)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.String("ovm.change.status", state.Status.ValueString()))
+
+	resp.Diagnostics.Append(r.readRisks(ctx, change.GetMetadata().GetUUID(), &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update sends the configured properties on top of the change's current
+// ones, so the planned changes, raw plan and snapshots recorded by change
+// analysis are kept.
+func (r *changeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Change Update")
+	defer span.End()
+
+	var plan changeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state changeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.change.id", state.ID.ValueString()),
+		attribute.String("ovm.change.title", plan.Title.ValueString()),
+	)
+
+	current, err := r.getChange(ctx, state.ID.ValueString(), false)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetChange failed")
+		return
+	}
+
+	props := &sdp.ChangeProperties{}
+	if current.GetProperties() != nil {
+		props = proto.Clone(current.GetProperties()).(*sdp.ChangeProperties)
+	}
+	resp.Diagnostics.Append(plan.applyProperties(ctx, props)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateResp, err := r.changes.UpdateChange(ctx, connect.NewRequest(&sdp.UpdateChangeRequest{
+		UUID:       current.GetMetadata().GetUUID(),
+		Properties: props,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateChange failed")
+		return
+	}
+
+	change := updateResp.Msg.GetChange()
+	resp.Diagnostics.Append(plan.setChange(change)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.readRisks(ctx, change.GetMetadata().GetUUID(), &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes changes that are still being defined. Once a change has
+// started it is part of the account's history, so it is left in Overmind and
+// only dropped from state.
+func (r *changeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Change Delete")
+	defer span.End()
+
+	var state changeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.id", state.ID.ValueString()))
+
+	change, err := r.getChange(ctx, state.ID.ValueString(), true)
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.change.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetChange failed")
+		return
+	}
+
+	status := change.GetMetadata().GetStatus()
+	span.SetAttributes(attribute.String("ovm.change.status", status.String()))
+	if status != sdp.ChangeStatus_CHANGE_STATUS_DEFINING {
+		span.SetAttributes(attribute.Bool("ovm.change.kept", true))
+		resp.Diagnostics.AddWarning("Change kept in Overmind",
+			fmt.Sprintf("Change %s has status %s, so it was removed from Terraform state but not deleted.",
+				state.ID.ValueString(), status))
+		return
+	}
+
+	_, err = r.changes.DeleteChange(ctx, connect.NewRequest(&sdp.DeleteChangeRequest{
+		UUID: change.GetMetadata().GetUUID(),
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.change.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete change", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteChange failed")
+	}
+}
+
+func (r *changeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Change Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.change.id", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// getChange fetches a change by its string UUID. A slim change leaves out
+// the raw plan and code changes, which are only needed when writing the
+// properties back.
+func (r *changeResource) getChange(ctx context.Context, id string, slim bool) (*sdp.Change, error) {
+	uuidBytes, err := uuidToBytes(id)
+	if err != nil {
+		return nil, err
+	}
+	getResp, err := r.changes.GetChange(ctx, connect.NewRequest(&sdp.GetChangeRequest{
+		UUID: uuidBytes,
+		Slim: slim,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return getResp.Msg.GetChange(), nil
+}
+
+// readRisks sets the risk counts from the change's latest analysis.
+func (r *changeResource) readRisks(ctx context.Context, changeUUID []byte, m *changeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	risksResp, err := r.changes.GetChangeRisks(ctx, connect.NewRequest(&sdp.GetChangeRisksRequest{
+		UUID: changeUUID,
+	}))
+	if err != nil {
+		diags.AddError("Failed to read change risks", err.Error())
+		return diags
+	}
+	md := risksResp.Msg.GetChangeRiskMetadata()
+	m.LowRiskCount = types.Int64Value(int64(md.GetNumLowRisk()))
+	m.MediumRiskCount = types.Int64Value(int64(md.GetNumMediumRisk()))
+	m.HighRiskCount = types.Int64Value(int64(md.GetNumHighRisk()))
+	return diags
+}
+
+// applyProperties overwrites the fields of props that this resource manages.
+// User tags are replaced; tags Overmind added automatically are kept.
+func (m *changeResourceModel) applyProperties(ctx context.Context, props *sdp.ChangeProperties) diag.Diagnostics {
+	props.Title = m.Title.ValueString()
+	props.Description = m.Description.ValueString()
+	props.TicketLink = m.TicketLink.ValueString()
+	props.Owner = m.Owner.ValueString()
+	props.CcEmails = m.CcEmails.ValueString()
+	props.Repo = m.Repo.ValueString()
+
+	var tags map[string]string
+	diags := m.Tags.ElementsAs(ctx, &tags, false)
+	if diags.HasError() {
+		return diags
+	}
+	tagValues := make(map[string]*sdp.TagValue, len(tags))
+	for k, v := range props.GetEnrichedTags().GetTagValue() {
+		if v.GetAutoTagValue() != nil {
+			tagValues[k] = v
+		}
+	}
+	for k, v := range tags {
+		tagValues[k] = &sdp.TagValue{Value: &sdp.TagValue_UserTagValue{UserTagValue: &sdp.UserTagValue{Value: v}}}
+	}
+	props.EnrichedTags = &sdp.EnrichedTags{TagValue: tagValues}
+	return diags
+}
+
+// setChange copies the API representation of the change into the model.
+func (m *changeResourceModel) setChange(change *sdp.Change) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	md := change.GetMetadata()
+	id, err := uuid.FromBytes(md.GetUUID())
+	if err != nil {
+		diags.AddError("Failed to parse change UUID", err.Error())
+		return diags
+	}
+
+	props := change.GetProperties()
+	m.ID = types.StringValue(id.String())
+	m.Title = types.StringValue(props.GetTitle())
+	m.Description = stringOrNull(props.GetDescription())
+	m.TicketLink = stringOrNull(props.GetTicketLink())
+	m.Owner = stringOrNull(props.GetOwner())
+	m.CcEmails = stringOrNull(props.GetCcEmails())
+	m.Repo = stringOrNull(props.GetRepo())
+	m.Status = types.StringValue(md.GetStatus().String())
+	m.Created = timestampString(md.GetCreatedAt())
+
+	m.BlastRadiusSnapshotID = types.StringNull()
+	if b := props.GetBlastRadiusSnapshotUUID(); len(b) > 0 {
+		snapshotID, err := uuid.FromBytes(b)
+		if err != nil {
+			diags.AddError("Failed to parse blast radius snapshot UUID", err.Error())
+			return diags
+		}
+		m.BlastRadiusSnapshotID = types.StringValue(snapshotID.String())
+	}
+
+	tags := map[string]attr.Value{}
+	for k, v := range props.GetEnrichedTags().GetTagValue() {
+		if user := v.GetUserTagValue(); user != nil {
+			tags[k] = types.StringValue(user.GetValue())
+		}
+	}
+	// An empty tags map in the configuration stays empty rather than
+	// becoming null.
+	if len(tags) > 0 || (!m.Tags.IsNull() && !m.Tags.IsUnknown()) {
+		var d diag.Diagnostics
+		m.Tags, d = types.MapValue(types.StringType, tags)
+		diags.Append(d...)
+	} else {
+		m.Tags = types.MapNull(types.StringType)
+	}
+	return diags
+}
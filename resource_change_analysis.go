@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	_ resource.Resource                   = (*changeAnalysisResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*changeAnalysisResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*changeAnalysisResource)(nil)
+)
+
+type changeAnalysisResource struct {
+	changes sdpconnect.ChangesServiceClient
+}
+
+type changeAnalysisResourceModel struct {
+	ID                types.String              `tfsdk:"id"`
+	ChangeID          types.String              `tfsdk:"change_id"`
+	PlanFile          types.String              `tfsdk:"plan_file"`
+	PlanSHA256        types.String              `tfsdk:"plan_sha256"`
+	SignalConfigFile  types.String              `tfsdk:"signal_config_file"`
+	KnowledgeFiles    []types.String            `tfsdk:"knowledge_files"`
+	BlastRadius       *blastRadiusOverrideModel `tfsdk:"blast_radius"`
+	Status            types.String              `tfsdk:"status"`
+	ChangingItemCount types.Int64               `tfsdk:"changing_item_count"`
+	Timeouts          *timeoutsModel            `tfsdk:"timeouts"`
+}
+
+type blastRadiusOverrideModel struct {
+	MaxItems                     types.Int64  `tfsdk:"max_items"`
+	LinkDepth                    types.Int64  `tfsdk:"link_depth"`
+	ChangeAnalysisTargetDuration types.String `tfsdk:"change_analysis_target_duration"`
+}
+
+func NewChangeAnalysisResource() resource.Resource {
+	return &changeAnalysisResource{}
+}
+
+func (r *changeAnalysisResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_change_analysis"
+}
+
+func (r *changeAnalysisResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Submits a Terraform plan to Overmind for blast radius and risk analysis of a change, and " +
+			"waits for the analysis to finish so its risks can be checked later in the same run. The analysis " +
+			"runs again whenever the plan file's contents or any other argument changes. Destroying the " +
+			"resource leaves the analysis results on the change.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "UUID of the analysed change.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"change_id": schema.StringAttribute{
+				Description: "UUID of the change to analyse, usually overmind_change.<name>.id.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"plan_file": schema.StringAttribute{
+				Description: "Path to the JSON representation of the Terraform plan, as written by " +
+					"`terraform show -json`. Every managed resource the plan changes is submitted as a changing item.",
+				Required: true,
+			},
+			"plan_sha256": schema.StringAttribute{
+				Description: "SHA-256 checksum of the plan file that was analysed.",
+				Computed:    true,
+			},
+			"signal_config_file": schema.StringAttribute{
+				Description: "Path to a signal config YAML file whose routine_changes_config and " +
+					"github_organisation_profile override the account settings for this analysis.",
+				Optional: true,
+			},
+			"knowledge_files": schema.ListAttribute{
+				Description: "Paths to files of extra knowledge about the infrastructure, such as Markdown " +
+					"runbooks, for the analysis to take into account. Each is named after its file name.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"blast_radius": schema.SingleNestedAttribute{
+				Description: "Blast radius limits to use instead of the account's blast radius preset.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"max_items": schema.Int64Attribute{
+						Description: "Maximum number of items in the blast radius.",
+						Required:    true,
+					},
+					"link_depth": schema.Int64Attribute{
+						Description: "Maximum number of links followed from each changing item.",
+						Required:    true,
+					},
+					"change_analysis_target_duration": schema.StringAttribute{
+						Description: "Target duration of the analysis as a Go duration string, between 1m and 30m.",
+						Optional:    true,
+					},
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the analysis: STATUS_DONE or STATUS_SKIPPED once it has finished.",
+				Computed:    true,
+			},
+			"changing_item_count": schema.Int64Attribute{
+				Description: "Number of changing items submitted from the plan.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}
+}
+
+func (r *changeAnalysisResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(config.Timeouts.validate()...)
+
+	if !config.ChangeID.IsNull() && !config.ChangeID.IsUnknown() {
+		if _, err := uuidToBytes(config.ChangeID.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("change_id"), "Invalid change ID", err.Error())
+		}
+	}
+
+	if config.BlastRadius != nil {
+		if d := config.BlastRadius.ChangeAnalysisTargetDuration; !d.IsNull() && !d.IsUnknown() {
+			if _, err := time.ParseDuration(d.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("blast_radius").AtName("change_analysis_target_duration"),
+					"Invalid target duration",
+					fmt.Sprintf("change_analysis_target_duration must be a Go duration string such as \"10m\": %s", err))
+			}
+		}
+	}
+}
+
+// ModifyPlan checksums the plan file so that a new plan written to the same
+// path triggers another analysis. If the file does not exist yet the checksum
+// is left unknown until apply.
+func (r *changeAnalysisResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.PlanFile.IsUnknown() {
+		return
+	}
+
+	checksum := types.StringUnknown()
+	if sum, err := fileSHA256(plan.PlanFile.ValueString()); err == nil {
+		checksum = types.StringValue(sum)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("plan_sha256"), checksum)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var state changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !checksum.Equal(state.PlanSHA256) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("changing_item_count"), types.Int64Unknown())...)
+	}
+}
+
+func (r *changeAnalysisResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.changes = clients.Changes
+}
+
+func (r *changeAnalysisResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "ChangeAnalysis Create")
+	defer span.End()
+
+	var plan changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.CreateTimeout(defaultChangeAnalysisTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.analyse(ctx, &plan, timeout)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "analysis failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read refreshes the analysis status. The plan and the other inputs are only
+// known from the configuration, so they are left as they are.
+func (r *changeAnalysisResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "ChangeAnalysis Read")
+	defer span.End()
+
+	var state changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.change.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid change ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	risksResp, err := r.changes.GetChangeRisks(ctx, connect.NewRequest(&sdp.GetChangeRisksRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.change.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read change analysis", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetChangeRisks failed")
+		return
+	}
+
+	state.Status = types.StringValue(risksResp.Msg.GetChangeRiskMetadata().GetChangeAnalysisStatus().GetStatus().String())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *changeAnalysisResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "ChangeAnalysis Update")
+	defer span.End()
+
+	var plan changeAnalysisResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.UpdateTimeout(defaultChangeAnalysisTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.analyse(ctx, &plan, timeout)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "analysis failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from state. The results of an analysis
+// belong to the change and cannot be removed from it.
+func (r *changeAnalysisResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	_, span := tracing.Tracer().Start(ctx, "ChangeAnalysis Delete")
+	defer span.End()
+}
+
+// analyse submits the plan for analysis and waits for it to finish, filling
+// in the computed attributes of m.
+func (r *changeAnalysisResource) analyse(ctx context.Context, m *changeAnalysisResourceModel, timeout time.Duration) diag.Diagnostics {
+	ctx, span := tracing.Tracer().Start(ctx, "ChangeAnalysis Analyse")
+	defer span.End()
+
+	var diags diag.Diagnostics
+	span.SetAttributes(
+		attribute.String("ovm.change.id", m.ChangeID.ValueString()),
+		attribute.String("ovm.change.planFile", m.PlanFile.ValueString()),
+	)
+
+	changeUUID, err := uuidToBytes(m.ChangeID.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("change_id"), "Invalid change ID", err.Error())
+		return diags
+	}
+
+	checksum, err := fileSHA256(m.PlanFile.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("plan_file"), "Failed to read plan file", err.Error())
+		return diags
+	}
+	tfPlan, err := readPlanFile(m.PlanFile.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("plan_file"), "Failed to read plan file", err.Error())
+		return diags
+	}
+	changingItems, err := mappedItemDiffsFromPlan(tfPlan)
+	if err != nil {
+		diags.AddAttributeError(path.Root("plan_file"), "Failed to map plan", err.Error())
+		return diags
+	}
+	span.SetAttributes(attribute.Int("ovm.change.changingItems", len(changingItems)))
+
+	analysisReq := &sdp.StartChangeAnalysisRequest{
+		ChangeUUID:    changeUUID,
+		ChangingItems: changingItems,
+	}
+
+	if !m.SignalConfigFile.IsNull() {
+		b, err := os.ReadFile(m.SignalConfigFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("signal_config_file"), "Failed to read signal config file", err.Error())
+			return diags
+		}
+		signalConfig, err := sdp.YamlStringToSignalConfig(string(b))
+		if err != nil {
+			diags.AddAttributeError(path.Root("signal_config_file"), "Invalid signal config file", err.Error())
+			return diags
+		}
+		analysisReq.RoutineChangesConfigOverride = signalConfig.RoutineChangesConfig
+		analysisReq.GithubOrganisationProfileOverride = signalConfig.GithubOrganisationProfile
+	}
+
+	for i, name := range m.KnowledgeFiles {
+		content, err := os.ReadFile(name.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("knowledge_files").AtListIndex(i), "Failed to read knowledge file", err.Error())
+			return diags
+		}
+		fileName := filepath.Base(name.ValueString())
+		analysisReq.Knowledge = append(analysisReq.Knowledge, &sdp.Knowledge{
+			Name:     strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+			Content:  string(content),
+			FileName: fileName,
+		})
+	}
+
+	if m.BlastRadius != nil {
+		analysisReq.BlastRadiusConfigOverride = &sdp.BlastRadiusConfig{
+			MaxItems:  int32(m.BlastRadius.MaxItems.ValueInt64()),  //nolint:gosec // small configured limit
+			LinkDepth: int32(m.BlastRadius.LinkDepth.ValueInt64()), //nolint:gosec // small configured limit
+		}
+		if !m.BlastRadius.ChangeAnalysisTargetDuration.IsNull() {
+			// Checked by ValidateConfig.
+			d, _ := time.ParseDuration(m.BlastRadius.ChangeAnalysisTargetDuration.ValueString())
+			analysisReq.BlastRadiusConfigOverride.ChangeAnalysisTargetDuration = durationpb.New(d)
+		}
+	}
+
+	startedAt := time.Now()
+	_, err = r.changes.StartChangeAnalysis(ctx, connect.NewRequest(analysisReq))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			diags.AddAttributeError(path.Root("change_id"), "Change not found",
+				fmt.Sprintf("No change with ID %s exists.", m.ChangeID.ValueString()))
+		} else {
+			diags.AddError("Failed to start change analysis", err.Error())
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "StartChangeAnalysis failed")
+		return diags
+	}
+
+	md, err := waitForChangeAnalysis(ctx, r.changes, changeUUID, startedAt, timeout)
+	if err != nil {
+		if errors.Is(err, errChangeAnalysisFailed) {
+			diags.AddError("Change analysis failed",
+				fmt.Sprintf("Overmind could not analyse change %s. Check the change in the Overmind UI for details.",
+					m.ChangeID.ValueString()))
+		} else {
+			diags.AddError("Failed to wait for change analysis", err.Error())
+		}
+		return diags
+	}
+
+	status := md.GetChangeAnalysisStatus().GetStatus()
+	if status == sdp.ChangeAnalysisStatus_STATUS_SKIPPED {
+		diags.AddWarning("Change analysis skipped",
+			fmt.Sprintf("Overmind skipped the analysis of change %s, so no risks were calculated.", m.ChangeID.ValueString()))
+	}
+
+	m.ID = m.ChangeID
+	m.PlanSHA256 = types.StringValue(checksum)
+	m.Status = types.StringValue(status.String())
+	m.ChangingItemCount = types.Int64Value(int64(len(changingItems)))
+	return diags
+}
+
+func fileSHA256(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
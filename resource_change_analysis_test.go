@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"golang.org/x/oauth2"
+)
+
+// StartChangeAnalysis records the request and marks the change's analysis as
+// in progress, clearing any earlier risks. With analysisPickupPolls set, the
+// earlier results stay in place until the analysis is picked up.
+func (m *mockChangesHandler) StartChangeAnalysis(_ context.Context, req *connect.Request[sdp.StartChangeAnalysisRequest]) (*connect.Response[sdp.StartChangeAnalysisResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(req.Msg.GetChangeUUID())
+	if err != nil {
+		return nil, err
+	}
+	m.analyses = append(m.analyses, req.Msg)
+	if m.analysisPickupPolls > 0 {
+		m.pickupPolls[uuid.UUID(change.GetMetadata().GetUUID()).String()] = m.analysisPickupPolls
+	} else {
+		m.startAnalysis(change)
+	}
+	return connect.NewResponse(&sdp.StartChangeAnalysisResponse{}), nil
+}
+
+// startAnalysis puts the change's analysis in progress. m.mu must be held.
+func (m *mockChangesHandler) startAnalysis(change *sdp.Change) {
+	id := uuid.UUID(change.GetMetadata().GetUUID()).String()
+	m.analysisPolls[id] = 2
+	m.risks[id] = &sdp.ChangeRiskMetadata{
+		ChangeAnalysisStatus: &sdp.ChangeAnalysisStatus{Status: sdp.ChangeAnalysisStatus_STATUS_INPROGRESS},
+	}
+	change.Metadata.ChangeAnalysisStatus = m.risks[id].GetChangeAnalysisStatus()
+}
+
+func shortenChangeAnalysisPolling(t *testing.T) {
+	t.Helper()
+	prevInterval, prevGrace := changeAnalysisPollInterval, changeAnalysisPickupGrace
+	changeAnalysisPollInterval = 10 * time.Millisecond
+	changeAnalysisPickupGrace = time.Second
+	t.Cleanup(func() { changeAnalysisPollInterval, changeAnalysisPickupGrace = prevInterval, prevGrace })
+}
+
+// startAnalysedChange creates a change through the mock and starts its
+// analysis, returning the change UUID.
+func startAnalysedChange(t *testing.T, mocks *mockServer) []byte {
+	t.Helper()
+	ctx := context.Background()
+	createResp, err := mocks.changes.CreateChange(ctx, connect.NewRequest(&sdp.CreateChangeRequest{
+		Properties: &sdp.ChangeProperties{Title: "Resize RDS instance"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeUUID := createResp.Msg.GetChange().GetMetadata().GetUUID()
+	if _, err := mocks.changes.StartChangeAnalysis(ctx, connect.NewRequest(&sdp.StartChangeAnalysisRequest{
+		ChangeUUID: changeUUID,
+	})); err != nil {
+		t.Fatal(err)
+	}
+	return changeUUID
+}
+
+func TestWaitForChangeAnalysis(t *testing.T) {
+	shortenChangeAnalysisPolling(t)
+	serverURL, mocks := startMockServer(t)
+	ctx := context.Background()
+	clients := testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL)
+
+	md, err := waitForChangeAnalysis(ctx, clients.Changes, startAnalysedChange(t, mocks), time.Now(), time.Second)
+	if err != nil {
+		t.Fatalf("waitForChangeAnalysis: %v", err)
+	}
+	if got := md.GetChangeAnalysisStatus().GetStatus(); got != sdp.ChangeAnalysisStatus_STATUS_DONE {
+		t.Errorf("expected STATUS_DONE, got %s", got)
+	}
+
+	mocks.changes.mu.Lock()
+	mocks.changes.analysisOutcome = sdp.ChangeAnalysisStatus_STATUS_ERROR
+	mocks.changes.mu.Unlock()
+	_, err = waitForChangeAnalysis(ctx, clients.Changes, startAnalysedChange(t, mocks), time.Now(), time.Second)
+	if !errors.Is(err, errChangeAnalysisFailed) {
+		t.Errorf("expected errChangeAnalysisFailed, got %v", err)
+	}
+
+	changeUUID := startAnalysedChange(t, mocks)
+	mocks.changes.mu.Lock()
+	mocks.changes.analysisPolls[uuid.UUID(changeUUID).String()] = 1000
+	mocks.changes.mu.Unlock()
+	_, err = waitForChangeAnalysis(ctx, clients.Changes, changeUUID, time.Now(), 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestWaitForChangeAnalysis_IgnoresPreviousResult(t *testing.T) {
+	shortenChangeAnalysisPolling(t)
+	serverURL, mocks := startMockServer(t)
+	ctx := context.Background()
+	clients := testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL)
+
+	// reanalyse starts a new analysis of a change whose previous analysis
+	// found a high risk, with the old result served for pickupPolls polls.
+	reanalyse := func(pickupPolls int) ([]byte, time.Time) {
+		createResp, err := mocks.changes.CreateChange(ctx, connect.NewRequest(&sdp.CreateChangeRequest{
+			Properties: &sdp.ChangeProperties{Title: "Resize RDS instance"},
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		changeUUID := createResp.Msg.GetChange().GetMetadata().GetUUID()
+		mocks.changes.setChangeRisks(uuid.UUID(changeUUID).String(), sdp.ChangeAnalysisStatus_STATUS_DONE,
+			&sdp.Risk{Title: "Orders API outage", Severity: sdp.Risk_SEVERITY_HIGH})
+		mocks.changes.mu.Lock()
+		mocks.changes.analysisPickupPolls = pickupPolls
+		mocks.changes.mu.Unlock()
+
+		startedAt := time.Now()
+		if _, err := mocks.changes.StartChangeAnalysis(ctx, connect.NewRequest(&sdp.StartChangeAnalysisRequest{
+			ChangeUUID: changeUUID,
+		})); err != nil {
+			t.Fatal(err)
+		}
+		return changeUUID, startedAt
+	}
+
+	changeUUID, startedAt := reanalyse(1)
+	md, err := waitForChangeAnalysis(ctx, clients.Changes, changeUUID, startedAt, time.Second)
+	if err != nil {
+		t.Fatalf("waitForChangeAnalysis: %v", err)
+	}
+	if len(md.GetRisks()) != 0 {
+		t.Errorf("expected the new analysis' results, got the previous risks %v", md.GetRisks())
+	}
+
+	// If the analysis is never seen in progress, the result is trusted once
+	// the grace period is over.
+	changeAnalysisPickupGrace = 50 * time.Millisecond
+	changeUUID, startedAt = reanalyse(1000)
+	md, err = waitForChangeAnalysis(ctx, clients.Changes, changeUUID, startedAt, time.Second)
+	if err != nil {
+		t.Fatalf("waitForChangeAnalysis: %v", err)
+	}
+	if time.Since(startedAt) < changeAnalysisPickupGrace {
+		t.Error("returned before the grace period was over")
+	}
+	if len(md.GetRisks()) != 1 {
+		t.Errorf("expected the only available result, got %v", md.GetRisks())
+	}
+
+	// An analysis that never starts is not mistaken for a finished one.
+	createResp, err := mocks.changes.CreateChange(ctx, connect.NewRequest(&sdp.CreateChangeRequest{
+		Properties: &sdp.ChangeProperties{Title: "Rotate TLS certificates"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeUUID = createResp.Msg.GetChange().GetMetadata().GetUUID()
+	startedAt = time.Now()
+	if _, err := mocks.changes.StartChangeAnalysis(ctx, connect.NewRequest(&sdp.StartChangeAnalysisRequest{
+		ChangeUUID: changeUUID,
+	})); err != nil {
+		t.Fatal(err)
+	}
+	_, err = waitForChangeAnalysis(ctx, clients.Changes, changeUUID, startedAt, 200*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "waiting for change analysis to start") {
+		t.Errorf("expected a timeout waiting for the analysis to start, got %v", err)
+	}
+}
+
+func testAccChangeAnalysisConfig(planFile, extra string) string {
+	return `resource "overmind_change" "release" {
+  title = "Release 2026.10.4"
+}
+
+resource "overmind_change_analysis" "release" {
+  change_id = overmind_change.release.id
+  plan_file = "` + planFile + `"
+` + extra + `
+}`
+}
+
+func TestChangeAnalysisResource(t *testing.T) {
+	shortenChangeAnalysisPolling(t)
+	serverURL, mocks := startMockServer(t)
+
+	dir := t.TempDir()
+	planFile := filepath.Join(dir, "plan.json")
+	if err := os.WriteFile(planFile, []byte(testPlanJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	signalConfigFile := filepath.Join(dir, "signal-config.yaml")
+	if err := os.WriteFile(signalConfigFile, []byte("routine_changes_config:\n  sensitivity: 0\n  duration_in_days: 7\n  events_per_day: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	knowledgeFile := filepath.Join(dir, "databases.md")
+	if err := os.WriteFile(knowledgeFile, []byte("Resizing orders causes a short failover."), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccChangeAnalysisConfig(planFile, `
+  signal_config_file = "`+signalConfigFile+`"
+  knowledge_files    = ["`+knowledgeFile+`"]
+
+  blast_radius = {
+    max_items                       = 500
+    link_depth                      = 3
+    change_analysis_target_duration = "10m"
+  }`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPair("overmind_change_analysis.release", "id", "overmind_change.release", "id"),
+					tfresource.TestCheckResourceAttr("overmind_change_analysis.release", "status", "STATUS_DONE"),
+					tfresource.TestCheckResourceAttr("overmind_change_analysis.release", "changing_item_count", "4"),
+					tfresource.TestCheckResourceAttrSet("overmind_change_analysis.release", "plan_sha256"),
+					func(_ *terraform.State) error {
+						mocks.changes.mu.Lock()
+						defer mocks.changes.mu.Unlock()
+						if len(mocks.changes.analyses) != 1 {
+							return fmt.Errorf("expected 1 analysis, got %d", len(mocks.changes.analyses))
+						}
+						req := mocks.changes.analyses[0]
+						if got := req.GetRoutineChangesConfigOverride().GetDuration(); got != 7 {
+							return fmt.Errorf("expected routine changes duration 7, got %v", got)
+						}
+						if got := req.GetBlastRadiusConfigOverride().GetChangeAnalysisTargetDuration().AsDuration(); got != 10*time.Minute {
+							return fmt.Errorf("expected target duration 10m, got %s", got)
+						}
+						if k := req.GetKnowledge(); len(k) != 1 || k[0].GetName() != "databases" || k[0].GetFileName() != "databases.md" {
+							return fmt.Errorf("unexpected knowledge: %v", k)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// A new plan written to the same path is analysed again, and
+				// the first poll still sees the previous analysis.
+				PreConfig: func() {
+					mocks.changes.mu.Lock()
+					mocks.changes.analysisPickupPolls = 1
+					mocks.changes.mu.Unlock()
+					plan := strings.Replace(testPlanJSON, `"actions": ["no-op"]`, `"actions": ["update"]`, 1)
+					if err := os.WriteFile(planFile, []byte(plan), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccChangeAnalysisConfig(planFile, ""),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_change_analysis.release", "changing_item_count", "5"),
+					func(_ *terraform.State) error {
+						mocks.changes.mu.Lock()
+						defer mocks.changes.mu.Unlock()
+						if len(mocks.changes.analyses) != 2 {
+							return fmt.Errorf("expected 2 analyses, got %d", len(mocks.changes.analyses))
+						}
+						if mocks.changes.analyses[1].BlastRadiusConfigOverride != nil {
+							return fmt.Errorf("expected no blast radius override")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestChangeAnalysisResource_Failed(t *testing.T) {
+	shortenChangeAnalysisPolling(t)
+	serverURL, mocks := startMockServer(t)
+	mocks.changes.analysisOutcome = sdp.ChangeAnalysisStatus_STATUS_ERROR
+
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planFile, []byte(testPlanJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config:      testAccChangeAnalysisConfig(planFile, ""),
+				ExpectError: regexp.MustCompile(`Change analysis failed`),
+			},
+		},
+	})
+}
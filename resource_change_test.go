@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- mock ChangesService handler ---
+
+type mockChangesHandler struct {
+	sdpconnect.UnimplementedChangesServiceHandler
+	mu sync.Mutex
+	// changes are the summaries ListHomeChanges returns.
+	changes []*sdp.ChangeSummary
+	// full holds the changes managed through CreateChange, by UUID, and
+	// risks their analysis results.
+	full  map[string]*sdp.Change
+	risks map[string]*sdp.ChangeRiskMetadata
+	// analyses records StartChangeAnalysis requests. An analysis stays in
+	// progress for analysisPolls GetChangeRisks calls, then finishes with
+	// analysisOutcome, or STATUS_DONE if that is unset.
+	analyses        []*sdp.StartChangeAnalysisRequest
+	analysisPolls   map[string]int
+	analysisOutcome sdp.ChangeAnalysisStatus_Status
+	// analysisPickupPolls is how many GetChangeRisks calls after
+	// StartChangeAnalysis still return the previous analysis, as happens
+	// until Overmind picks up the job.
+	analysisPickupPolls int
+	pickupPolls         map[string]int
+	// stallSnapshots makes StartChange and EndChange hang after taking the
+	// snapshot until the client gives up.
+	stallSnapshots bool
+}
+
+func newMockChangesHandler() *mockChangesHandler {
+	return &mockChangesHandler{
+		full:          make(map[string]*sdp.Change),
+		risks:         make(map[string]*sdp.ChangeRiskMetadata),
+		analysisPolls: make(map[string]int),
+		pickupPolls:   make(map[string]int),
+	}
+}
+
+func (m *mockChangesHandler) lookupChange(b []byte) (*sdp.Change, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	change, ok := m.full[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return change, nil
+}
+
+// CreateChange records the change as being defined. Overmind adds an
+// automatic tag to every change, which the resource must leave alone.
+func (m *mockChangesHandler) CreateChange(_ context.Context, req *connect.Request[sdp.CreateChangeRequest]) (*connect.Response[sdp.CreateChangeResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	props := req.Msg.GetProperties()
+	if props.GetEnrichedTags() == nil {
+		props.EnrichedTags = &sdp.EnrichedTags{}
+	}
+	if props.EnrichedTags.TagValue == nil {
+		props.EnrichedTags.TagValue = make(map[string]*sdp.TagValue)
+	}
+	props.EnrichedTags.TagValue["environment"] = &sdp.TagValue{
+		Value: &sdp.TagValue_AutoTagValue{AutoTagValue: &sdp.AutoTagValue{Value: "production", Reasoning: "mock"}},
+	}
+	id := uuid.New()
+	change := &sdp.Change{
+		Metadata: &sdp.ChangeMetadata{
+			UUID:      id[:],
+			CreatedAt: timestamppb.Now(),
+			UpdatedAt: timestamppb.Now(),
+			Status:    sdp.ChangeStatus_CHANGE_STATUS_DEFINING,
+		},
+		Properties: props,
+	}
+	m.full[id.String()] = change
+	return connect.NewResponse(&sdp.CreateChangeResponse{Change: change}), nil
+}
+
+func (m *mockChangesHandler) GetChange(_ context.Context, req *connect.Request[sdp.GetChangeRequest]) (*connect.Response[sdp.GetChangeResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&sdp.GetChangeResponse{Change: change}), nil
+}
+
+func (m *mockChangesHandler) UpdateChange(_ context.Context, req *connect.Request[sdp.UpdateChangeRequest]) (*connect.Response[sdp.UpdateChangeResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	change.Properties = req.Msg.GetProperties()
+	change.Metadata.UpdatedAt = timestamppb.Now()
+	return connect.NewResponse(&sdp.UpdateChangeResponse{Change: change}), nil
+}
+
+func (m *mockChangesHandler) DeleteChange(_ context.Context, req *connect.Request[sdp.DeleteChangeRequest]) (*connect.Response[sdp.DeleteChangeResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	if change.GetMetadata().GetStatus() != sdp.ChangeStatus_CHANGE_STATUS_DEFINING {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("change is %s", change.GetMetadata().GetStatus()))
+	}
+	delete(m.full, uuid.UUID(change.GetMetadata().GetUUID()).String())
+	delete(m.risks, uuid.UUID(change.GetMetadata().GetUUID()).String())
+	return connect.NewResponse(&sdp.DeleteChangeResponse{}), nil
+}
+
+func (m *mockChangesHandler) GetChangeRisks(_ context.Context, req *connect.Request[sdp.GetChangeRisksRequest]) (*connect.Response[sdp.GetChangeRisksResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change, err := m.lookupChange(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	id := uuid.UUID(change.GetMetadata().GetUUID()).String()
+	if polls, ok := m.pickupPolls[id]; ok {
+		if polls > 0 {
+			m.pickupPolls[id]--
+			return connect.NewResponse(&sdp.GetChangeRisksResponse{ChangeRiskMetadata: m.risks[id]}), nil
+		}
+		delete(m.pickupPolls, id)
+		m.startAnalysis(change)
+	}
+	md, ok := m.risks[id]
+	if ok && md.GetChangeAnalysisStatus().GetStatus() == sdp.ChangeAnalysisStatus_STATUS_INPROGRESS {
+		m.analysisPolls[id]--
+		if m.analysisPolls[id] <= 0 {
+			outcome := m.analysisOutcome
+			if outcome == sdp.ChangeAnalysisStatus_STATUS_UNSPECIFIED {
+				outcome = sdp.ChangeAnalysisStatus_STATUS_DONE
+			}
+			md.ChangeAnalysisStatus = &sdp.ChangeAnalysisStatus{Status: outcome}
+			change.Metadata.ChangeAnalysisStatus = md.GetChangeAnalysisStatus()
+		}
+	}
+	if !ok {
+		md = &sdp.ChangeRiskMetadata{
+			ChangeAnalysisStatus: &sdp.ChangeAnalysisStatus{Status: sdp.ChangeAnalysisStatus_STATUS_UNSPECIFIED},
+		}
+	}
+	return connect.NewResponse(&sdp.GetChangeRisksResponse{ChangeRiskMetadata: md}), nil
+}
+
+// setChangeStatus moves a change to status, as starting it from the CLI would.
+func (m *mockChangesHandler) setChangeStatus(id string, status sdp.ChangeStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.full[id].Metadata.Status = status
+}
+
+// setChangeRisks records the result of analysing a change, counting the
+// risks by severity.
+func (m *mockChangesHandler) setChangeRisks(id string, status sdp.ChangeAnalysisStatus_Status, risks ...*sdp.Risk) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	md := &sdp.ChangeRiskMetadata{
+		ChangeAnalysisStatus: &sdp.ChangeAnalysisStatus{Status: status},
+		Risks:                risks,
+	}
+	for _, risk := range risks {
+		switch risk.GetSeverity() {
+		case sdp.Risk_SEVERITY_LOW:
+			md.NumLowRisk++
+		case sdp.Risk_SEVERITY_MEDIUM:
+			md.NumMediumRisk++
+		case sdp.Risk_SEVERITY_HIGH:
+			md.NumHighRisk++
+		}
+	}
+	m.risks[id] = md
+	if change, ok := m.full[id]; ok {
+		change.Metadata.ChangeAnalysisStatus = md.GetChangeAnalysisStatus()
+	}
+}
+
+func testAccChangeConfig(title, tags string) string {
+	return `resource "overmind_change" "release" {
+  title       = "` + title + `"
+  description = "Weekly release of the checkout service"
+  ticket_link = "https://jira.example.com/browse/REL-42"
+  owner       = "release-bot"
+  cc_emails   = "oncall@example.com, sre@example.com"
+  repo        = "github.com/example/checkout"
+  tags        = ` + tags + `
+}`
+}
+
+func TestChangeResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	var id string
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if len(mocks.changes.full) != 0 {
+				return fmt.Errorf("expected the defining change to be deleted, %d remain", len(mocks.changes.full))
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccChangeConfig("Release 2026.10.1", `{ team = "payments" }`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_change.release", "status", "CHANGE_STATUS_DEFINING"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "tags.%", "1"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "tags.team", "payments"),
+					tfresource.TestCheckNoResourceAttr("overmind_change.release", "blast_radius_snapshot_id"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "high_risk_count", "0"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["overmind_change.release"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Analysis results recorded out of band survive an update.
+				PreConfig: func() {
+					mocks.changes.mu.Lock()
+					snapshotID := uuid.New()
+					mocks.changes.full[id].Properties.RawPlan = `{"format_version":"1.2"}`
+					mocks.changes.full[id].Properties.BlastRadiusSnapshotUUID = snapshotID[:]
+					mocks.changes.mu.Unlock()
+					mocks.changes.setChangeRisks(id, sdp.ChangeAnalysisStatus_STATUS_DONE,
+						&sdp.Risk{Title: "Checkout downtime", Severity: sdp.Risk_SEVERITY_HIGH},
+						&sdp.Risk{Title: "Cache flush", Severity: sdp.Risk_SEVERITY_LOW},
+					)
+				},
+				Config: testAccChangeConfig("Release 2026.10.2", `{ team = "payments", tier = "1" }`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPtr("overmind_change.release", "id", &id),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "title", "Release 2026.10.2"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "tags.%", "2"),
+					tfresource.TestCheckResourceAttrSet("overmind_change.release", "blast_radius_snapshot_id"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "high_risk_count", "1"),
+					tfresource.TestCheckResourceAttr("overmind_change.release", "low_risk_count", "1"),
+					func(_ *terraform.State) error {
+						mocks.changes.mu.Lock()
+						defer mocks.changes.mu.Unlock()
+						props := mocks.changes.full[id].GetProperties()
+						if props.GetRawPlan() == "" {
+							return fmt.Errorf("update dropped the raw plan")
+						}
+						if props.GetEnrichedTags().GetTagValue()["environment"].GetAutoTagValue() == nil {
+							return fmt.Errorf("update dropped the automatic tag")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "overmind_change.release",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestChangeResource_DeleteStartedChange(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	var id string
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, ok := mocks.changes.full[id]; !ok {
+				return fmt.Errorf("started change %s was deleted", id)
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccChangeConfig("Release 2026.10.3", `{}`),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_change.release", "tags.%", "0"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["overmind_change.release"].Primary.ID
+						mocks.changes.setChangeStatus(id, sdp.ChangeStatus_CHANGE_STATUS_HAPPENING)
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
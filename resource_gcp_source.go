@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const gcpSourceType = "gcp"
+
+// gcpOwnedConfigKeys lists every config key that overmind_gcp_source manages.
+// Any other key on the source is preserved on update.
+var gcpOwnedConfigKeys = []string{
+	"gcp-project-ids",
+	"gcp-regions",
+	"gcp-impersonation-service-account-email",
+}
+
+var (
+	_ resource.Resource                = (*gcpSourceResource)(nil)
+	_ resource.ResourceWithIdentity    = (*gcpSourceResource)(nil)
+	_ resource.ResourceWithImportState = (*gcpSourceResource)(nil)
+)
+
+type gcpSourceResource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type gcpSourceResourceModel struct {
+	ID                               types.String `tfsdk:"id"`
+	Name                             types.String `tfsdk:"name"`
+	GCPProjectIDs                    types.List   `tfsdk:"gcp_project_ids"`
+	GCPRegions                       types.List   `tfsdk:"gcp_regions"`
+	ImpersonationServiceAccountEmail types.String `tfsdk:"impersonation_service_account_email"`
+	OvermindServiceAccountName       types.String `tfsdk:"overmind_service_account_name"`
+	Health                           types.Object `tfsdk:"health"`
+}
+
+func NewGCPSourceResource() resource.Resource {
+	return &gcpSourceResource{}
+}
+
+func (r *gcpSourceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcp_source"
+}
+
+func (r *gcpSourceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = sourceIdentitySchema()
+}
+
+func (r *gcpSourceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an Overmind GCP infrastructure source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Source UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Human-readable name for this source.",
+				Required:    true,
+			},
+			"gcp_project_ids": schema.ListAttribute{
+				Description: "GCP project IDs this source should discover resources in.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"gcp_regions": schema.ListAttribute{
+				Description: "GCP regions this source should discover resources in. " +
+					"If unset, the source discovers resources in all regions.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"impersonation_service_account_email": schema.StringAttribute{
+				Description: "Email of the service account in the customer's GCP organisation that " +
+					"Overmind impersonates to discover resources.",
+				Required: true,
+			},
+			"overmind_service_account_name": schema.StringAttribute{
+				Description: "Name of the Overmind-side service account for this Overmind account. " +
+					"Grant it roles/iam.serviceAccountTokenCreator on the impersonated service account.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"health": sourceHealthSchemaAttribute(),
+		},
+	}
+}
+
+func (r *gcpSourceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+}
+
+func (r *gcpSourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource Create")
+	defer span.End()
+
+	var plan gcpSourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.source.name", plan.Name.ValueString()),
+		attribute.String("ovm.source.serviceAccount", plan.ImpersonationServiceAccountEmail.ValueString()),
+	)
+
+	saName, err := r.overmindServiceAccountName(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get Overmind account", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccount failed")
+		return
+	}
+
+	sourceConfig, diags := plan.sourceConfig(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sourceConfigStruct, err := structpb.NewStruct(sourceConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build source config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "config build failed")
+		return
+	}
+
+	createResp, err := r.mgmt.CreateSource(ctx, connect.NewRequest(&sdp.CreateSourceRequest{
+		Properties: &sdp.SourceProperties{
+			DescriptiveName: plan.Name.ValueString(),
+			Type:            gcpSourceType,
+			Config:          sourceConfigStruct,
+		},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateSource failed")
+		return
+	}
+
+	source := createResp.Msg.GetSource()
+	sourceUUID, err := uuid.FromBytes(source.GetMetadata().GetUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse source UUID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UUID parse failed")
+		return
+	}
+
+	plan.ID = types.StringValue(sourceUUID.String())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: plan.ID})...)
+	plan.OvermindServiceAccountName = types.StringValue(saName)
+
+	span.SetAttributes(attribute.String("ovm.source.id", sourceUUID.String()))
+
+	health, diags := readSourceHealth(ctx, r.mgmt, source.GetMetadata().GetUUID())
+	resp.Diagnostics.Append(diags...)
+	plan.Health = health
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *gcpSourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource Read")
+	defer span.End()
+
+	var state gcpSourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.source.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	getResp, err := r.mgmt.GetSource(ctx, connect.NewRequest(&sdp.GetSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.source.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSource failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setSourceProperties(ctx, getResp.Msg.GetSource().GetProperties())...)
+
+	// The service account name only depends on the Overmind account, so it
+	// is fetched once on create/import and then kept in state.
+	if state.OvermindServiceAccountName.IsNull() || state.OvermindServiceAccountName.ValueString() == "" {
+		saName, err := r.overmindServiceAccountName(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get Overmind account", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetAccount failed")
+			return
+		}
+		state.OvermindServiceAccountName = types.StringValue(saName)
+	}
+
+	health, diags := readSourceHealth(ctx, r.mgmt, uuidBytes)
+	resp.Diagnostics.Append(diags...)
+	state.Health = health
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: state.ID})...)
+}
+
+func (r *gcpSourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource Update")
+	defer span.End()
+
+	var plan gcpSourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state gcpSourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.source.id", state.ID.ValueString()),
+		attribute.String("ovm.source.name", plan.Name.ValueString()),
+	)
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	sourceConfig, diags := plan.sourceConfig(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read the current source first so that config keys and additional
+	// config written by the UI or the backend survive the update.
+	getResp, err := r.mgmt.GetSource(ctx, connect.NewRequest(&sdp.GetSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSource failed")
+		return
+	}
+	current := getResp.Msg.GetSource().GetProperties()
+
+	sourceConfigStruct, unmanaged, err := mergeSourceConfig(current.GetConfig(), gcpOwnedConfigKeys, sourceConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build source config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "config build failed")
+		return
+	}
+
+	if len(unmanaged) > 0 {
+		span.SetAttributes(attribute.StringSlice("ovm.source.unmanagedKeys", unmanaged))
+		resp.Diagnostics.AddWarning("Source has unmanaged config keys",
+			fmt.Sprintf("Source %s has config keys that are not managed by this resource and were left unchanged: %s. "+
+				"These were most likely set in the Overmind UI.", state.ID.ValueString(), strings.Join(unmanaged, ", ")))
+	}
+
+	_, err = r.mgmt.UpdateSource(ctx, connect.NewRequest(&sdp.UpdateSourceRequest{
+		UUID: uuidBytes,
+		Properties: &sdp.SourceProperties{
+			DescriptiveName:  plan.Name.ValueString(),
+			Type:             gcpSourceType,
+			Config:           sourceConfigStruct,
+			AdditionalConfig: current.GetAdditionalConfig(),
+		},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateSource failed")
+		return
+	}
+
+	health, diags := readSourceHealth(ctx, r.mgmt, uuidBytes)
+	resp.Diagnostics.Append(diags...)
+	plan.Health = health
+
+	plan.ID = state.ID
+	plan.OvermindServiceAccountName = state.OvermindServiceAccountName
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *gcpSourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource Delete")
+	defer span.End()
+
+	var state gcpSourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.source.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.mgmt.DeleteSource(ctx, connect.NewRequest(&sdp.DeleteSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.source.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteSource failed")
+	}
+}
+
+func (r *gcpSourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GCPSource Import")
+	defer span.End()
+
+	importSourceOfType(ctx, r.mgmt, gcpSourceType, req, resp)
+}
+
+// overmindServiceAccountName derives the name of the Overmind-side GCP service
+// account from the name of the current Overmind account.
+func (r *gcpSourceResource) overmindServiceAccountName(ctx context.Context) (string, error) {
+	accountResp, err := r.mgmt.GetAccount(ctx, connect.NewRequest(&sdp.GetAccountRequest{}))
+	if err != nil {
+		return "", err
+	}
+	accountName := accountResp.Msg.GetAccount().GetProperties().GetName()
+	saName := sdp.GcpSANameFromAccountName(accountName)
+	if saName == "" {
+		return "", fmt.Errorf("cannot derive a GCP service account name from account name %q", accountName)
+	}
+	return saName, nil
+}
+
+// setSourceProperties copies the API representation of the source into the
+// model.
+func (m *gcpSourceResourceModel) setSourceProperties(ctx context.Context, props *sdp.SourceProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.Name = types.StringValue(props.GetDescriptiveName())
+
+	if cfg := props.GetConfig(); cfg != nil {
+		fields := cfg.GetFields()
+		if v, ok := fields["gcp-project-ids"]; ok {
+			listVal, d := types.ListValueFrom(ctx, types.StringType, stringsFromStructValue(v))
+			diags.Append(d...)
+			m.GCPProjectIDs = listVal
+		}
+		if v, ok := fields["gcp-regions"]; ok {
+			listVal, d := types.ListValueFrom(ctx, types.StringType, stringsFromStructValue(v))
+			diags.Append(d...)
+			m.GCPRegions = listVal
+		} else {
+			m.GCPRegions = types.ListNull(types.StringType)
+		}
+		if v, ok := fields["gcp-impersonation-service-account-email"]; ok {
+			m.ImpersonationServiceAccountEmail = types.StringValue(v.GetStringValue())
+		}
+	}
+
+	return diags
+}
+
+// sourceConfig builds the GCP source config keys from the plan.
+func (m *gcpSourceResourceModel) sourceConfig(ctx context.Context) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	projectIDs, d := stringsFromList(ctx, m.GCPProjectIDs)
+	diags.Append(d...)
+
+	fields := map[string]any{
+		"gcp-project-ids":                         toAnySlice(projectIDs),
+		"gcp-impersonation-service-account-email": m.ImpersonationServiceAccountEmail.ValueString(),
+	}
+	if !m.GCPRegions.IsNull() {
+		regions, d := stringsFromList(ctx, m.GCPRegions)
+		diags.Append(d...)
+		fields["gcp-regions"] = toAnySlice(regions)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+	return fields, diags
+}
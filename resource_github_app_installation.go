@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// GitHub app installation states reported in the status attribute.
+const (
+	githubAppNotInstalled    = "NOT_INSTALLED"
+	githubAppPendingApproval = "PENDING_APPROVAL"
+	githubAppInstalled       = "INSTALLED"
+	githubAppSuspended       = "SUSPENDED"
+)
+
+var (
+	_ resource.Resource                = (*githubAppInstallationResource)(nil)
+	_ resource.ResourceWithImportState = (*githubAppInstallationResource)(nil)
+)
+
+type githubAppInstallationResource struct {
+	mgmt   sdpconnect.ManagementServiceClient
+	config sdpconnect.ConfigurationServiceClient
+}
+
+type githubAppInstallationResourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	InstallationID        types.Int64  `tfsdk:"installation_id"`
+	InstallURL            types.String `tfsdk:"install_url"`
+	Status                types.String `tfsdk:"status"`
+	OrganisationName      types.String `tfsdk:"organisation_name"`
+	InstalledBy           types.String `tfsdk:"installed_by"`
+	InstalledAt           types.String `tfsdk:"installed_at"`
+	RequestedBy           types.String `tfsdk:"requested_by"`
+	CanCreateChecks       types.Bool   `tfsdk:"can_create_checks"`
+	ActiveRepositoryCount types.Int64  `tfsdk:"active_repository_count"`
+	ContributorCount      types.Int64  `tfsdk:"contributor_count"`
+}
+
+func NewGithubAppInstallationResource() resource.Resource {
+	return &githubAppInstallationResource{}
+}
+
+func (r *githubAppInstallationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_github_app_installation"
+}
+
+func (r *githubAppInstallationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Connects the Overmind GitHub app to the account. There is one per account. Create it without an " +
+			"installation_id to get an install_url, install the app from there, then set installation_id to the ID " +
+			"GitHub reports. Destroying the resource disconnects the app.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Name of the Overmind account the installation belongs to.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"installation_id": schema.Int64Attribute{
+				Description: "GitHub app installation ID. If unset, an installation made through install_url is adopted " +
+					"as soon as it completes. Removing it from configuration leaves the app connected; destroy the " +
+					"resource to disconnect it.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"install_url": schema.StringAttribute{
+				Description: "URL to visit to install the GitHub app on an organisation.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Installation status: NOT_INSTALLED, PENDING_APPROVAL while a GitHub organisation admin " +
+					"has yet to approve a request to install the app, INSTALLED, or SUSPENDED.",
+				Computed: true,
+			},
+			"organisation_name": schema.StringAttribute{
+				Description: "GitHub organisation the app is installed on, or that installation was requested for.",
+				Computed:    true,
+			},
+			"installed_by": schema.StringAttribute{
+				Description: "User who installed the app.",
+				Computed:    true,
+			},
+			"installed_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the app was installed.",
+				Computed:    true,
+			},
+			"requested_by": schema.StringAttribute{
+				Description: "User who requested installation, while status is PENDING_APPROVAL.",
+				Computed:    true,
+			},
+			"can_create_checks": schema.BoolAttribute{
+				Description: "Whether the installation has the checks:write permission needed to report check runs.",
+				Computed:    true,
+			},
+			"active_repository_count": schema.Int64Attribute{
+				Description: "Number of active repositories the app can see.",
+				Computed:    true,
+			},
+			"contributor_count": schema.Int64Attribute{
+				Description: "Number of contributors across those repositories.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *githubAppInstallationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+	r.config = clients.Configuration
+}
+
+func (r *githubAppInstallationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GithubAppInstallation Create")
+	defer span.End()
+
+	var plan githubAppInstallationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accountResp, err := r.mgmt.GetAccount(ctx, connect.NewRequest(&sdp.GetAccountRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get account", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccount failed")
+		return
+	}
+	plan.ID = types.StringValue(accountResp.Msg.GetAccount().GetProperties().GetName())
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	if !plan.InstallationID.IsUnknown() && !plan.InstallationID.IsNull() {
+		_, err = r.mgmt.SetGithubInstallationID(ctx, connect.NewRequest(&sdp.SetGithubInstallationIDRequest{
+			GithubInstallationId: plan.InstallationID.ValueInt64(),
+		}))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to set GitHub app installation ID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "SetGithubInstallationID failed")
+			return
+		}
+	}
+
+	urlResp, err := r.config.CreateGithubInstallURL(ctx, connect.NewRequest(&sdp.CreateGithubInstallURLRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create GitHub app install URL", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateGithubInstallURL failed")
+		return
+	}
+	plan.InstallURL = types.StringValue(urlResp.Msg.GetInstallUrl())
+
+	info, err := r.readGithubAppInformation(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read GitHub app information", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetGithubAppInformation failed")
+		return
+	}
+	plan.setGithubAppInformation(info)
+	span.SetAttributes(attribute.String("ovm.githubApp.status", plan.Status.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *githubAppInstallationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GithubAppInstallation Read")
+	defer span.End()
+
+	var state githubAppInstallationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	info, err := r.readGithubAppInformation(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read GitHub app information", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetGithubAppInformation failed")
+		return
+	}
+	state.setGithubAppInformation(info)
+	span.SetAttributes(attribute.String("ovm.githubApp.status", state.Status.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *githubAppInstallationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GithubAppInstallation Update")
+	defer span.End()
+
+	var plan, state githubAppInstallationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	if !plan.InstallationID.IsUnknown() && !plan.InstallationID.IsNull() && !plan.InstallationID.Equal(state.InstallationID) {
+		span.SetAttributes(attribute.Int64("ovm.githubApp.installationId", plan.InstallationID.ValueInt64()))
+		_, err := r.mgmt.SetGithubInstallationID(ctx, connect.NewRequest(&sdp.SetGithubInstallationIDRequest{
+			GithubInstallationId: plan.InstallationID.ValueInt64(),
+		}))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to set GitHub app installation ID", err.Error())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "SetGithubInstallationID failed")
+			return
+		}
+	}
+
+	info, err := r.readGithubAppInformation(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read GitHub app information", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetGithubAppInformation failed")
+		return
+	}
+	plan.setGithubAppInformation(info)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *githubAppInstallationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GithubAppInstallation Delete")
+	defer span.End()
+
+	var state githubAppInstallationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	if state.InstallationID.IsNull() {
+		span.SetAttributes(attribute.Bool("ovm.githubApp.notInstalled", true))
+		return
+	}
+
+	_, err := r.mgmt.UnsetGithubInstallationID(ctx, connect.NewRequest(&sdp.UnsetGithubInstallationIDRequest{}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.githubApp.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to disconnect GitHub app", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UnsetGithubInstallationID failed")
+	}
+}
+
+func (r *githubAppInstallationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "GithubAppInstallation Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.account.name", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// readGithubAppInformation returns the account's GitHub app information, or
+// nil if the app has never been installed.
+func (r *githubAppInstallationResource) readGithubAppInformation(ctx context.Context) (*sdp.GithubAppInformation, error) {
+	infoResp, err := r.config.GetGithubAppInformation(ctx, connect.NewRequest(&sdp.GetGithubAppInformationRequest{}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return infoResp.Msg.GetGithubAppInformation(), nil
+}
+
+func (m *githubAppInstallationResourceModel) setGithubAppInformation(info *sdp.GithubAppInformation) {
+	m.InstallationID = types.Int64Null()
+	if info.GetInstallationID() != 0 {
+		m.InstallationID = types.Int64Value(info.GetInstallationID())
+	}
+
+	switch {
+	case info.GetSuspended():
+		m.Status = types.StringValue(githubAppSuspended)
+	case info.GetInstallationID() != 0:
+		m.Status = types.StringValue(githubAppInstalled)
+	case info.GetRequestedOrgName() != "":
+		m.Status = types.StringValue(githubAppPendingApproval)
+	default:
+		m.Status = types.StringValue(githubAppNotInstalled)
+	}
+
+	m.OrganisationName = stringOrNull(info.GetOrganisationName())
+	if m.OrganisationName.IsNull() {
+		m.OrganisationName = stringOrNull(info.GetRequestedOrgName())
+	}
+	m.InstalledBy = stringOrNull(info.GetInstalledBy())
+	m.InstalledAt = timestampString(info.GetInstalledAt())
+	m.RequestedBy = stringOrNull(info.GetRequestedBy())
+	m.CanCreateChecks = types.BoolValue(info.GetCanCreateChecks())
+	m.ActiveRepositoryCount = types.Int64Value(info.GetActiveRepositoryCount())
+	m.ContributorCount = types.Int64Value(info.GetContributorCount())
+	if m.InstallURL.IsUnknown() {
+		m.InstallURL = types.StringNull()
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (m *mockMgmtHandler) SetGithubInstallationID(_ context.Context, req *connect.Request[sdp.SetGithubInstallationIDRequest]) (*connect.Response[sdp.SetGithubInstallationIDResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.githubInstallationID = req.Msg.GetGithubInstallationId()
+	return connect.NewResponse(&sdp.SetGithubInstallationIDResponse{}), nil
+}
+
+func (m *mockMgmtHandler) UnsetGithubInstallationID(_ context.Context, _ *connect.Request[sdp.UnsetGithubInstallationIDRequest]) (*connect.Response[sdp.UnsetGithubInstallationIDResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.githubInstallationID = 0
+	return connect.NewResponse(&sdp.UnsetGithubInstallationIDResponse{}), nil
+}
+
+func (m *mockConfigHandler) GetGithubAppInformation(_ context.Context, _ *connect.Request[sdp.GetGithubAppInformationRequest]) (*connect.Response[sdp.GetGithubAppInformationResponse], error) {
+	m.mgmt.mu.Lock()
+	defer m.mgmt.mu.Unlock()
+	info := &sdp.GithubAppInformation{}
+	if id := m.mgmt.githubInstallationID; id != 0 {
+		info = &sdp.GithubAppInformation{
+			InstallationID:        id,
+			InstalledBy:           "octocat",
+			InstalledAt:           timestamppb.Now(),
+			OrganisationName:      "example-org",
+			ActiveRepositoryCount: 12,
+			ContributorCount:      30,
+		}
+	}
+	return connect.NewResponse(&sdp.GetGithubAppInformationResponse{GithubAppInformation: info}), nil
+}
+
+func (m *mockConfigHandler) CreateGithubInstallURL(_ context.Context, _ *connect.Request[sdp.CreateGithubInstallURLRequest]) (*connect.Response[sdp.CreateGithubInstallURLResponse], error) {
+	return connect.NewResponse(&sdp.CreateGithubInstallURLResponse{
+		InstallUrl: "https://github.com/apps/overmind/installations/new?state=test",
+	}), nil
+}
+
+func (m *mockConfigHandler) RegenerateGithubAppProfile(_ context.Context, _ *connect.Request[sdp.RegenerateGithubAppProfileRequest]) (*connect.Response[sdp.RegenerateGithubAppProfileResponse], error) {
+	m.mgmt.mu.Lock()
+	defer m.mgmt.mu.Unlock()
+	if m.mgmt.githubInstallationID == 0 {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("no GitHub installation"))
+	}
+	return connect.NewResponse(&sdp.RegenerateGithubAppProfileResponse{
+		GithubOrganisationProfile: &sdp.GithubOrganisationProfile{PrimaryBranchName: "main"},
+	}), nil
+}
+
+func TestGithubAppInstallationResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if mocks.mgmt.githubInstallationID != 0 {
+				return fmt.Errorf("GitHub installation not unset")
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_github_app_installation" "this" {}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_github_app_installation.this", "status", "NOT_INSTALLED"),
+					tfresource.TestCheckResourceAttrSet("overmind_github_app_installation.this", "install_url"),
+					tfresource.TestCheckNoResourceAttr("overmind_github_app_installation.this", "installation_id"),
+				),
+			},
+			{
+				Config: `resource "overmind_github_app_installation" "this" {
+  installation_id = 4242
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_github_app_installation.this", "status", "INSTALLED"),
+					tfresource.TestCheckResourceAttr("overmind_github_app_installation.this", "organisation_name", "example-org"),
+					tfresource.TestCheckResourceAttr("overmind_github_app_installation.this", "active_repository_count", "12"),
+					func(_ *terraform.State) error {
+						if mocks.mgmt.githubInstallationID != 4242 {
+							return fmt.Errorf("expected installation ID 4242, got %d", mocks.mgmt.githubInstallationID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Disconnected outside of Terraform: reconnected.
+				PreConfig: func() {
+					mocks.mgmt.mu.Lock()
+					mocks.mgmt.githubInstallationID = 0
+					mocks.mgmt.mu.Unlock()
+				},
+				Config: `resource "overmind_github_app_installation" "this" {
+  installation_id = 4242
+}`,
+				Check: tfresource.TestCheckResourceAttr("overmind_github_app_installation.this", "status", "INSTALLED"),
+			},
+		},
+	})
+}
+
+func TestRegenerateGithubAppProfileAction_Invoke(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+	ctx := context.Background()
+
+	a := NewRegenerateGithubAppProfileAction().(*regenerateGithubAppProfileAction)
+	var configureResp action.ConfigureResponse
+	a.Configure(ctx, action.ConfigureRequest{
+		ProviderData: testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL),
+	}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{}),
+	}
+
+	invoke := func() ([]string, action.InvokeResponse) {
+		var progress []string
+		resp := action.InvokeResponse{
+			SendProgress: func(event action.InvokeProgressEvent) { progress = append(progress, event.Message) },
+		}
+		a.Invoke(ctx, action.InvokeRequest{Config: config}, &resp)
+		return progress, resp
+	}
+
+	_, resp := invoke()
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "GitHub app not installed" {
+		t.Errorf("expected not installed error, got %v", resp.Diagnostics)
+	}
+
+	mocks.mgmt.githubInstallationID = 4242
+	progress, resp := invoke()
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Invoke: %v", resp.Diagnostics)
+	}
+	if !strings.HasSuffix(progress[len(progress)-1], `primary branch "main"`) {
+		t.Errorf("unexpected result message: %q", progress[len(progress)-1])
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ resource.Resource                = (*hcpTerraformIntegrationResource)(nil)
+	_ resource.ResourceWithImportState = (*hcpTerraformIntegrationResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*hcpTerraformIntegrationResource)(nil)
+)
+
+type hcpTerraformIntegrationResource struct {
+	mgmt     sdpconnect.ManagementServiceClient
+	config   sdpconnect.ConfigurationServiceClient
+	instance sdp.OvermindInstance
+}
+
+type hcpTerraformIntegrationResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	RotationTrigger types.String `tfsdk:"rotation_trigger"`
+	Endpoint        types.String `tfsdk:"endpoint"`
+	Secret          types.String `tfsdk:"secret"`
+	Status          types.String `tfsdk:"status"`
+	APIKeyID        types.String `tfsdk:"api_key_id"`
+	AuthorizeURL    types.String `tfsdk:"authorize_url"`
+}
+
+func NewHCPTerraformIntegrationResource() resource.Resource {
+	return &hcpTerraformIntegrationResource{}
+}
+
+func (r *hcpTerraformIntegrationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hcp_terraform_integration"
+}
+
+func (r *hcpTerraformIntegrationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the Overmind HCP Terraform run task integration. There is one per account. Pass endpoint " +
+			"and secret to a tfe_organization_run_task, and authorize the backing API key once by visiting authorize_url.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Name of the Overmind account the integration belongs to.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"rotation_trigger": schema.StringAttribute{
+				Description: "Arbitrary value that replaces the API key backing the integration when changed. " +
+					"The endpoint and secret are kept, but the new key must be authorized again.",
+				Optional: true,
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "Run task endpoint URL.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret": schema.StringAttribute{
+				Description: "Run task HMAC key.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Integration status, CONFIGURED or ERROR.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"api_key_id": schema.StringAttribute{
+				Description: "UUID of the API key the integration uses to talk to Overmind.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"authorize_url": schema.StringAttribute{
+				Description: "URL to visit to authorize the API key after the integration is created or its key replaced.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan marks the API key attributes as unknown when rotation_trigger
+// changes, since replacing the key issues a new one.
+func (r *hcpTerraformIntegrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.RotationTrigger.Equal(state.RotationTrigger) {
+		for _, attr := range []string{"api_key_id", "authorize_url"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+		}
+	}
+}
+
+func (r *hcpTerraformIntegrationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+	r.config = clients.Configuration
+	r.instance = clients.Instance
+}
+
+func (r *hcpTerraformIntegrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "HCPTerraformIntegration Create")
+	defer span.End()
+
+	var plan hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accountResp, err := r.mgmt.GetAccount(ctx, connect.NewRequest(&sdp.GetAccountRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get account", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccount failed")
+		return
+	}
+	plan.ID = types.StringValue(accountResp.Msg.GetAccount().GetProperties().GetName())
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	createResp, err := r.config.CreateHcpConfig(ctx, connect.NewRequest(&sdp.CreateHcpConfigRequest{
+		FinalFrontendRedirect: finalFrontendRedirect(r.instance),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create HCP Terraform integration", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateHcpConfig failed")
+		return
+	}
+
+	plan.setHcpConfig(createResp.Msg.GetConfig())
+	plan.Status = types.StringValue(sdp.GetHcpConfigResponse_CONFIGURED.String())
+	resp.Diagnostics.Append(plan.setAPIKey(createResp.Msg.GetApiKey())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(plan.authorizeWarning()...)
+}
+
+func (r *hcpTerraformIntegrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "HCPTerraformIntegration Read")
+	defer span.End()
+
+	var state hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	getResp, err := r.config.GetHcpConfig(ctx, connect.NewRequest(&sdp.GetHcpConfigRequest{}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.hcpConfig.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read HCP Terraform integration", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetHcpConfig failed")
+		return
+	}
+
+	state.setHcpConfig(getResp.Msg.GetConfig())
+	state.Status = types.StringValue(getResp.Msg.GetStatus().String())
+	span.SetAttributes(attribute.String("ovm.hcpConfig.status", state.Status.ValueString()))
+	if state.APIKeyID.IsUnknown() {
+		state.APIKeyID = types.StringNull()
+	}
+	if state.AuthorizeURL.IsUnknown() {
+		state.AuthorizeURL = types.StringNull()
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *hcpTerraformIntegrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "HCPTerraformIntegration Update")
+	defer span.End()
+
+	var plan hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	if plan.RotationTrigger.Equal(state.RotationTrigger) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	span.SetAttributes(attribute.Bool("ovm.hcpConfig.rotated", true))
+
+	replaceResp, err := r.config.ReplaceHcpApiKey(ctx, connect.NewRequest(&sdp.ReplaceHcpApiKeyRequest{
+		FinalFrontendRedirect: finalFrontendRedirect(r.instance),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to replace HCP Terraform integration API key", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ReplaceHcpApiKey failed")
+		return
+	}
+
+	plan.setHcpConfig(replaceResp.Msg.GetConfig())
+	resp.Diagnostics.Append(plan.setAPIKey(replaceResp.Msg.GetApiKey())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(plan.authorizeWarning()...)
+}
+
+func (r *hcpTerraformIntegrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "HCPTerraformIntegration Delete")
+	defer span.End()
+
+	var state hcpTerraformIntegrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	_, err := r.config.DeleteHcpConfig(ctx, connect.NewRequest(&sdp.DeleteHcpConfigRequest{}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.hcpConfig.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete HCP Terraform integration", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteHcpConfig failed")
+	}
+}
+
+func (r *hcpTerraformIntegrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "HCPTerraformIntegration Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.account.name", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (m *hcpTerraformIntegrationResourceModel) setHcpConfig(config *sdp.HcpConfig) {
+	m.Endpoint = types.StringValue(config.GetEndpoint())
+	m.Secret = types.StringValue(config.GetSecret())
+}
+
+// setAPIKey records the API key created for the integration.
+func (m *hcpTerraformIntegrationResourceModel) setAPIKey(apiKey *sdp.CreateAPIKeyResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keyUUID, err := uuid.FromBytes(apiKey.GetKey().GetMetadata().GetUuid())
+	if err != nil {
+		diags.AddError("Failed to parse API key UUID", err.Error())
+		return diags
+	}
+	m.APIKeyID = types.StringValue(keyUUID.String())
+	m.AuthorizeURL = stringOrNull(apiKey.GetAuthorizeURL())
+	return diags
+}
+
+// authorizeWarning reminds the user to authorize the integration's new API
+// key.
+func (m *hcpTerraformIntegrationResourceModel) authorizeWarning() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !m.AuthorizeURL.IsNull() {
+		diags.AddWarning("HCP Terraform integration API key must be authorized",
+			fmt.Sprintf("Run tasks will fail until the integration's API key has been authorized at %s", m.AuthorizeURL.ValueString()))
+	}
+	return diags
+}
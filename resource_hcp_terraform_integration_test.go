@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+)
+
+func mockHcpAPIKey() *sdp.CreateAPIKeyResponse {
+	id := uuid.New()
+	return &sdp.CreateAPIKeyResponse{
+		Key: &sdp.APIKey{
+			Metadata:   &sdp.APIKeyMetadata{Uuid: id[:], Status: sdp.KeyStatus_KEY_STATUS_UNAUTHORIZED},
+			Properties: &sdp.APIKeyProperties{Name: "HCP Terraform"},
+		},
+		AuthorizeURL: "https://auth.example.com/authorize?key=" + id.String(),
+	}
+}
+
+func (m *mockConfigHandler) CreateHcpConfig(_ context.Context, _ *connect.Request[sdp.CreateHcpConfigRequest]) (*connect.Response[sdp.CreateHcpConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hcpConfig != nil {
+		return nil, connect.NewError(connect.CodeAlreadyExists, nil)
+	}
+	m.hcpConfig = &sdp.HcpConfig{
+		Endpoint: "https://api.example.com/hcp/" + uuid.NewString(),
+		Secret:   uuid.NewString(),
+	}
+	return connect.NewResponse(&sdp.CreateHcpConfigResponse{Config: m.hcpConfig, ApiKey: mockHcpAPIKey()}), nil
+}
+
+func (m *mockConfigHandler) GetHcpConfig(_ context.Context, _ *connect.Request[sdp.GetHcpConfigRequest]) (*connect.Response[sdp.GetHcpConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hcpConfig == nil {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return connect.NewResponse(&sdp.GetHcpConfigResponse{Config: m.hcpConfig}), nil
+}
+
+func (m *mockConfigHandler) ReplaceHcpApiKey(_ context.Context, _ *connect.Request[sdp.ReplaceHcpApiKeyRequest]) (*connect.Response[sdp.ReplaceHcpApiKeyResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hcpConfig == nil {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return connect.NewResponse(&sdp.ReplaceHcpApiKeyResponse{Config: m.hcpConfig, ApiKey: mockHcpAPIKey()}), nil
+}
+
+func (m *mockConfigHandler) DeleteHcpConfig(_ context.Context, _ *connect.Request[sdp.DeleteHcpConfigRequest]) (*connect.Response[sdp.DeleteHcpConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hcpConfig == nil {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	m.hcpConfig = nil
+	return connect.NewResponse(&sdp.DeleteHcpConfigResponse{}), nil
+}
+
+func testAccHCPTerraformIntegrationConfig(rotation string) string {
+	return `resource "overmind_hcp_terraform_integration" "this" {
+  rotation_trigger = "` + rotation + `"
+}`
+}
+
+func TestHCPTerraformIntegrationResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	var endpoint, keyID string
+	capture := func(attr string, dst *string) tfresource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			*dst = s.RootModule().Resources["overmind_hcp_terraform_integration.this"].Primary.Attributes[attr]
+			return nil
+		}
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if mocks.config.hcpConfig != nil {
+				return fmt.Errorf("HCP config not deleted")
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccHCPTerraformIntegrationConfig("2026-01"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_hcp_terraform_integration.this", "endpoint"),
+					tfresource.TestCheckResourceAttrSet("overmind_hcp_terraform_integration.this", "secret"),
+					tfresource.TestCheckResourceAttr("overmind_hcp_terraform_integration.this", "status", "CONFIGURED"),
+					tfresource.TestCheckResourceAttrSet("overmind_hcp_terraform_integration.this", "authorize_url"),
+					capture("endpoint", &endpoint),
+					capture("api_key_id", &keyID),
+				),
+			},
+			{
+				// Replacing the key keeps the endpoint and secret.
+				Config: testAccHCPTerraformIntegrationConfig("2026-02"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPtr("overmind_hcp_terraform_integration.this", "endpoint", &endpoint),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["overmind_hcp_terraform_integration.this"].Primary.Attributes["api_key_id"]; id == keyID {
+							return fmt.Errorf("expected a new API key, still %s", id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Removed outside of Terraform: recreated.
+				PreConfig: func() {
+					mocks.config.mu.Lock()
+					mocks.config.hcpConfig = nil
+					mocks.config.mu.Unlock()
+				},
+				Config: testAccHCPTerraformIntegrationConfig("2026-02"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						if e := s.RootModule().Resources["overmind_hcp_terraform_integration.this"].Primary.Attributes["endpoint"]; e == endpoint {
+							return fmt.Errorf("expected a new endpoint, still %s", e)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// labelColourPattern matches the hex colour codes that the Overmind UI uses
+// for labels.
+var labelColourPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// labelRuleTestChangeCount is how many of the most recent changes a label
+// rule is tested against when test_against_recent_changes is set.
+const labelRuleTestChangeCount = 20
+
+var (
+	_ resource.Resource                   = (*labelRuleResource)(nil)
+	_ resource.ResourceWithImportState    = (*labelRuleResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*labelRuleResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*labelRuleResource)(nil)
+)
+
+type labelRuleResource struct {
+	labels  sdpconnect.LabelServiceClient
+	changes sdpconnect.ChangesServiceClient
+}
+
+type labelRuleResourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	Name                     types.String `tfsdk:"name"`
+	Colour                   types.String `tfsdk:"colour"`
+	Instructions             types.String `tfsdk:"instructions"`
+	TestAgainstRecentChanges types.Bool   `tfsdk:"test_against_recent_changes"`
+	CreatedAt                types.String `tfsdk:"created_at"`
+	UpdatedAt                types.String `tfsdk:"updated_at"`
+}
+
+func NewLabelRuleResource() resource.Resource {
+	return &labelRuleResource{}
+}
+
+func (r *labelRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_label_rule"
+}
+
+func (r *labelRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an Overmind label rule. Overmind labels each new change whose contents match " +
+			"the rule's instructions.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Label rule UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the label applied to matching changes.",
+				Required:    true,
+			},
+			"colour": schema.StringAttribute{
+				Description: "Colour of the label as a hex code, e.g. \"#FF5733\".",
+				Required:    true,
+			},
+			"instructions": schema.StringAttribute{
+				Description: "Plain-language description of the changes that should receive this label.",
+				Required:    true,
+			},
+			"test_against_recent_changes": schema.BoolAttribute{
+				Description: fmt.Sprintf("When true, plans that create or modify the rule test it against the %d most recent "+
+					"changes and report, as warnings, which of them would gain or lose the label. "+
+					"The rule is not applied to those changes.", labelRuleTestChangeCount),
+				Optional: true,
+			},
+			"created_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the rule was created.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"updated_at": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the rule was last updated.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *labelRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config labelRuleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Colour.IsNull() || config.Colour.IsUnknown() {
+		return
+	}
+
+	if !labelColourPattern.MatchString(config.Colour.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("colour"), "Invalid label colour",
+			fmt.Sprintf("colour must be a hex code in the form \"#RRGGBB\", got %q.", config.Colour.ValueString()))
+	}
+}
+
+// ModifyPlan keeps updated_at when nothing changes and, if requested, tests
+// new or modified rules against recent changes.
+func (r *labelRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan labelRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state *labelRuleResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &labelRuleResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if plan.propertiesEqual(state) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated_at"), state.UpdatedAt)...)
+			return
+		}
+	}
+
+	if !plan.TestAgainstRecentChanges.ValueBool() ||
+		plan.Name.IsUnknown() || plan.Colour.IsUnknown() || plan.Instructions.IsUnknown() {
+		return
+	}
+
+	var ruleUUID []byte
+	if state != nil {
+		id, err := uuid.Parse(state.ID.ValueString())
+		if err == nil {
+			ruleUUID = id[:]
+		}
+	}
+	resp.Diagnostics.Append(r.testAgainstRecentChanges(ctx, ruleUUID, plan.properties())...)
+}
+
+// testAgainstRecentChanges streams TestLabelRule over the most recent changes
+// and warns about every change whose labels would differ from today. Failures
+// are reported as warnings since the test is advisory.
+func (r *labelRuleResource) testAgainstRecentChanges(ctx context.Context, ruleUUID []byte, props *sdp.LabelRuleProperties) diag.Diagnostics {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Test")
+	defer span.End()
+
+	var diags diag.Diagnostics
+
+	listResp, err := r.changes.ListHomeChanges(ctx, connect.NewRequest(&sdp.ListHomeChangesRequest{
+		Pagination: &sdp.PaginationRequest{PageSize: labelRuleTestChangeCount, Page: 1},
+	}))
+	if err != nil {
+		diags.AddWarning("Failed to test label rule", fmt.Sprintf("Could not list recent changes: %s", err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListHomeChanges failed")
+		return diags
+	}
+
+	changes := listResp.Msg.GetChanges()
+	if len(changes) == 0 {
+		return diags
+	}
+
+	changeUUIDs := make([][]byte, len(changes))
+	for i, change := range changes {
+		changeUUIDs[i] = change.GetUUID()
+	}
+
+	stream, err := r.labels.TestLabelRule(ctx, connect.NewRequest(&sdp.TestLabelRuleRequest{
+		Properties: props,
+		ChangeUUID: changeUUIDs,
+	}))
+	if err != nil {
+		diags.AddWarning("Failed to test label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "TestLabelRule failed")
+		return diags
+	}
+	defer stream.Close()
+
+	applied := make(map[uuid.UUID]bool, len(changes))
+	for stream.Receive() {
+		msg := stream.Msg()
+		id, err := uuid.FromBytes(msg.GetChangeUUID())
+		if err != nil {
+			continue
+		}
+		applied[id] = msg.GetApplied()
+	}
+	if err := stream.Err(); err != nil {
+		diags.AddWarning("Failed to test label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "TestLabelRule stream failed")
+		return diags
+	}
+
+	gained, lost := labelRuleTestDiff(ruleUUID, changes, applied)
+	span.SetAttributes(
+		attribute.Int("ovm.labelRule.testedChanges", len(applied)),
+		attribute.Int("ovm.labelRule.gained", len(gained)),
+		attribute.Int("ovm.labelRule.lost", len(lost)),
+	)
+
+	if len(gained) > 0 {
+		diags.AddAttributeWarning(path.Root("instructions"), "Recent changes would gain label",
+			fmt.Sprintf("Of the %d most recent changes, these would be labelled %q by this rule:\n%s",
+				len(changes), props.GetName(), strings.Join(gained, "\n")))
+	}
+	if len(lost) > 0 {
+		diags.AddAttributeWarning(path.Root("instructions"), "Recent changes would lose label",
+			fmt.Sprintf("Of the %d most recent changes, these are labelled by this rule today and would no longer be:\n%s",
+				len(changes), strings.Join(lost, "\n")))
+	}
+	return diags
+}
+
+// labelRuleTestDiff compares TestLabelRule results with the labels the rule
+// has already applied, and describes the changes that would gain or lose the
+// label, in the order they were listed. Changes the test did not report on
+// are left out.
+func labelRuleTestDiff(ruleUUID []byte, changes []*sdp.ChangeSummary, applied map[uuid.UUID]bool) (gained, lost []string) {
+	for _, change := range changes {
+		id, err := uuid.FromBytes(change.GetUUID())
+		if err != nil {
+			continue
+		}
+		wouldApply, tested := applied[id]
+		if !tested {
+			continue
+		}
+
+		hasLabel := false
+		if ruleUUID != nil {
+			for _, label := range change.GetLabels() {
+				if bytes.Equal(label.GetLabelRuleUUID(), ruleUUID) && !label.GetSkipped() {
+					hasLabel = true
+					break
+				}
+			}
+		}
+
+		description := fmt.Sprintf("  - %s (%s)", change.GetTitle(), id)
+		switch {
+		case wouldApply && !hasLabel:
+			gained = append(gained, description)
+		case !wouldApply && hasLabel:
+			lost = append(lost, description)
+		}
+	}
+	return gained, lost
+}
+
+func (r *labelRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.labels = clients.Labels
+	r.changes = clients.Changes
+}
+
+func (r *labelRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Create")
+	defer span.End()
+
+	var plan labelRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.labelRule.name", plan.Name.ValueString()))
+
+	createResp, err := r.labels.CreateLabelRule(ctx, connect.NewRequest(&sdp.CreateLabelRuleRequest{
+		Properties: plan.properties(),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateLabelRule failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setLabelRule(createResp.Msg.GetRule())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(attribute.String("ovm.labelRule.id", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *labelRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Read")
+	defer span.End()
+
+	var state labelRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.labelRule.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid label rule ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	getResp, err := r.labels.GetLabelRule(ctx, connect.NewRequest(&sdp.GetLabelRuleRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.labelRule.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetLabelRule failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setLabelRule(getResp.Msg.GetRule())...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *labelRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Update")
+	defer span.End()
+
+	var plan labelRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state labelRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.labelRule.id", state.ID.ValueString()),
+		attribute.String("ovm.labelRule.name", plan.Name.ValueString()),
+	)
+
+	if plan.propertiesEqual(&state) {
+		// Only test_against_recent_changes changed, which is not sent to
+		// the API.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid label rule ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	updateResp, err := r.labels.UpdateLabelRule(ctx, connect.NewRequest(&sdp.UpdateLabelRuleRequest{
+		UUID:       uuidBytes,
+		Properties: plan.properties(),
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateLabelRule failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setLabelRule(updateResp.Msg.GetRule())...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *labelRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Delete")
+	defer span.End()
+
+	var state labelRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.labelRule.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid label rule ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.labels.DeleteLabelRule(ctx, connect.NewRequest(&sdp.DeleteLabelRuleRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.labelRule.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete label rule", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteLabelRule failed")
+	}
+}
+
+func (r *labelRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "LabelRule Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.labelRule.id", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (m *labelRuleResourceModel) properties() *sdp.LabelRuleProperties {
+	return &sdp.LabelRuleProperties{
+		Name:         m.Name.ValueString(),
+		Colour:       m.Colour.ValueString(),
+		Instructions: m.Instructions.ValueString(),
+	}
+}
+
+// propertiesEqual reports whether the attributes sent to the API are the same
+// in both models.
+func (m *labelRuleResourceModel) propertiesEqual(other *labelRuleResourceModel) bool {
+	return m.Name.Equal(other.Name) && m.Colour.Equal(other.Colour) && m.Instructions.Equal(other.Instructions)
+}
+
+// setLabelRule copies the API representation of the rule into the model.
+func (m *labelRuleResourceModel) setLabelRule(rule *sdp.LabelRule) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	ruleUUID, err := uuid.FromBytes(rule.GetMetadata().GetLabelRuleUUID())
+	if err != nil {
+		diags.AddError("Failed to parse label rule UUID", err.Error())
+		return diags
+	}
+
+	props := rule.GetProperties()
+	m.ID = types.StringValue(ruleUUID.String())
+	m.Name = types.StringValue(props.GetName())
+	m.Colour = types.StringValue(props.GetColour())
+	m.Instructions = types.StringValue(props.GetInstructions())
+	m.CreatedAt = timestampString(rule.GetMetadata().GetCreatedAt())
+	m.UpdatedAt = timestampString(rule.GetMetadata().GetUpdatedAt())
+	return diags
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- mock LabelService handler ---
+
+type mockLabelHandler struct {
+	sdpconnect.UnimplementedLabelServiceHandler
+	mu      sync.Mutex
+	rules   map[string]*sdp.LabelRule
+	changes *mockChangesHandler
+}
+
+// newMockLabelHandler returns a label mock that tests rules against the
+// changes stored in the given changes mock.
+func newMockLabelHandler(changes *mockChangesHandler) *mockLabelHandler {
+	return &mockLabelHandler{rules: make(map[string]*sdp.LabelRule), changes: changes}
+}
+
+func (m *mockLabelHandler) lookup(b []byte) (*sdp.LabelRule, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	rule, ok := m.rules[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return rule, nil
+}
+
+func (m *mockLabelHandler) CreateLabelRule(_ context.Context, req *connect.Request[sdp.CreateLabelRuleRequest]) (*connect.Response[sdp.CreateLabelRuleResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New()
+	rule := &sdp.LabelRule{
+		Metadata: &sdp.LabelRuleMetadata{
+			LabelRuleUUID: id[:],
+			CreatedAt:     timestamppb.Now(),
+			UpdatedAt:     timestamppb.Now(),
+		},
+		Properties: req.Msg.GetProperties(),
+	}
+	m.rules[id.String()] = rule
+	return connect.NewResponse(&sdp.CreateLabelRuleResponse{Rule: rule}), nil
+}
+
+func (m *mockLabelHandler) GetLabelRule(_ context.Context, req *connect.Request[sdp.GetLabelRuleRequest]) (*connect.Response[sdp.GetLabelRuleResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rule, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&sdp.GetLabelRuleResponse{Rule: rule}), nil
+}
+
+func (m *mockLabelHandler) UpdateLabelRule(_ context.Context, req *connect.Request[sdp.UpdateLabelRuleRequest]) (*connect.Response[sdp.UpdateLabelRuleResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rule, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	rule.Properties = req.Msg.GetProperties()
+	rule.Metadata.UpdatedAt = timestamppb.Now()
+	return connect.NewResponse(&sdp.UpdateLabelRuleResponse{Rule: rule}), nil
+}
+
+func (m *mockLabelHandler) DeleteLabelRule(_ context.Context, req *connect.Request[sdp.DeleteLabelRuleRequest]) (*connect.Response[sdp.DeleteLabelRuleResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rule, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	delete(m.rules, uuid.UUID(rule.GetMetadata().GetLabelRuleUUID()).String())
+	return connect.NewResponse(&sdp.DeleteLabelRuleResponse{}), nil
+}
+
+// TestLabelRule applies the rule to changes whose title contains its
+// instructions, ignoring case.
+func (m *mockLabelHandler) TestLabelRule(_ context.Context, req *connect.Request[sdp.TestLabelRuleRequest], stream *connect.ServerStream[sdp.TestLabelRuleResponse]) error {
+	props := req.Msg.GetProperties()
+	for _, changeUUID := range req.Msg.GetChangeUUID() {
+		title := m.changes.title(changeUUID)
+		applied := strings.Contains(strings.ToLower(title), strings.ToLower(props.GetInstructions()))
+		err := stream.Send(&sdp.TestLabelRuleResponse{
+			ChangeUUID: changeUUID,
+			Applied:    applied,
+			Label: &sdp.Label{
+				Type:   sdp.LabelType_LABEL_TYPE_AUTO,
+				Name:   props.GetName(),
+				Colour: props.GetColour(),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReapplyLabelRuleInTimeRange labels the changes created in the window that
+// the rule matches, and returns their UUIDs.
+func (m *mockLabelHandler) ReapplyLabelRuleInTimeRange(_ context.Context, req *connect.Request[sdp.ReapplyLabelRuleInTimeRangeRequest]) (*connect.Response[sdp.ReapplyLabelRuleInTimeRangeResponse], error) {
+	m.mu.Lock()
+	rule, err := m.lookup(req.Msg.GetUUID())
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.changes.mu.Lock()
+	defer m.changes.mu.Unlock()
+	var labelled [][]byte
+	for _, change := range m.changes.changes {
+		created := change.GetCreatedAt().AsTime()
+		if created.Before(req.Msg.GetStartAt().AsTime()) || created.After(req.Msg.GetEndAt().AsTime()) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(change.GetTitle()), strings.ToLower(rule.GetProperties().GetInstructions())) {
+			labelled = append(labelled, change.GetUUID())
+		}
+	}
+	return connect.NewResponse(&sdp.ReapplyLabelRuleInTimeRangeResponse{ChangeUUID: labelled}), nil
+}
+
+// --- mock ChangesService handler ---
+
+func (m *mockChangesHandler) ListHomeChanges(_ context.Context, req *connect.Request[sdp.ListHomeChangesRequest]) (*connect.Response[sdp.ListHomeChangesResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changes := m.changes
+	if size := int(req.Msg.GetPagination().GetPageSize()); size > 0 && len(changes) > size {
+		changes = changes[:size]
+	}
+	return connect.NewResponse(&sdp.ListHomeChangesResponse{Changes: changes}), nil
+}
+
+// addChangeSummary adds a change, newest first, carrying labels from the
+// given rules.
+func (m *mockChangesHandler) addChangeSummary(title string, labelRuleUUIDs ...[]byte) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.New()
+	change := &sdp.ChangeSummary{UUID: id[:], Title: title, CreatedAt: timestamppb.Now()}
+	for _, ruleUUID := range labelRuleUUIDs {
+		change.Labels = append(change.Labels, &sdp.Label{Type: sdp.LabelType_LABEL_TYPE_AUTO, LabelRuleUUID: ruleUUID})
+	}
+	m.changes = append([]*sdp.ChangeSummary{change}, m.changes...)
+	return id.String()
+}
+
+func (m *mockChangesHandler) title(changeUUID []byte) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range m.changes {
+		if bytes.Equal(change.GetUUID(), changeUUID) {
+			return change.GetTitle()
+		}
+	}
+	return ""
+}
+
+// --- tests ---
+
+func testAccLabelRuleConfig(name, colour, instructions string) string {
+	return `resource "overmind_label_rule" "test" {
+  name         = "` + name + `"
+  colour       = "` + colour + `"
+  instructions = "` + instructions + `"
+}`
+}
+
+func TestLabelRuleResource_CRUD(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccLabelRuleConfig("database", "#FF5733", "Changes to databases"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrSet("overmind_label_rule.test", "id"),
+					tfresource.TestCheckResourceAttr("overmind_label_rule.test", "name", "database"),
+					tfresource.TestCheckResourceAttr("overmind_label_rule.test", "colour", "#FF5733"),
+					tfresource.TestCheckResourceAttrSet("overmind_label_rule.test", "created_at"),
+				),
+			},
+			{
+				Config: testAccLabelRuleConfig("database", "#00AA00", "Changes to RDS or DynamoDB"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_label_rule.test", "colour", "#00AA00"),
+					tfresource.TestCheckResourceAttr("overmind_label_rule.test", "instructions", "Changes to RDS or DynamoDB"),
+				),
+			},
+			{
+				ResourceName:      "overmind_label_rule.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestLabelRuleResource_InvalidColour(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config:      testAccLabelRuleConfig("database", "red", "Changes to databases"),
+				ExpectError: regexp.MustCompile(`Invalid label colour`),
+			},
+		},
+	})
+}
+
+func TestLabelRuleResource_TestAgainstRecentChanges(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+	ctx := context.Background()
+
+	ruleUUID := uuid.New()
+	mocks.changes.addChangeSummary("Resize RDS instance")
+	mocks.changes.addChangeSummary("Rotate IAM keys", ruleUUID[:])
+	mocks.changes.addChangeSummary("Upgrade RDS engine", ruleUUID[:])
+
+	r := &labelRuleResource{}
+	var configureResp resource.ConfigureResponse
+	r.Configure(ctx, resource.ConfigureRequest{
+		ProviderData: testClients(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test"})), serverURL),
+	}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Configure: %v", configureResp.Diagnostics)
+	}
+
+	diags := r.testAgainstRecentChanges(ctx, ruleUUID[:], &sdp.LabelRuleProperties{
+		Name:         "database",
+		Colour:       "#FF5733",
+		Instructions: "rds",
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	summaries := make([]string, 0, len(diags))
+	for _, d := range diags {
+		summaries = append(summaries, d.Summary()+": "+d.Detail())
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected a gain and a loss warning, got %v", summaries)
+	}
+	if !strings.Contains(summaries[0], "would gain label") || !strings.Contains(summaries[0], "Resize RDS instance") ||
+		strings.Contains(summaries[0], "Upgrade RDS engine") {
+		t.Errorf("unexpected gain warning: %s", summaries[0])
+	}
+	if !strings.Contains(summaries[1], "would lose label") || !strings.Contains(summaries[1], "Rotate IAM keys") {
+		t.Errorf("unexpected loss warning: %s", summaries[1])
+	}
+
+	// A new rule has not labelled anything yet, so it can only gain.
+	diags = r.testAgainstRecentChanges(ctx, nil, &sdp.LabelRuleProperties{Name: "database", Instructions: "rds"})
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), "Upgrade RDS engine") ||
+		!strings.Contains(diags[0].Detail(), "Resize RDS instance") {
+		t.Errorf("unexpected diagnostics for new rule: %v", diags)
+	}
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.yaml.in/yaml/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+var routineChangesAttrTypes = map[string]attr.Type{ //nolint:gochecknoglobals // constant schema types
+	"sensitivity":      types.Float64Type,
+	"duration_in_days": types.Float64Type,
+	"events_per_day":   types.Float64Type,
+}
+
+var (
+	_ resource.Resource                   = (*signalConfigResource)(nil)
+	_ resource.ResourceWithImportState    = (*signalConfigResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*signalConfigResource)(nil)
+)
+
+type signalConfigResource struct {
+	mgmt   sdpconnect.ManagementServiceClient
+	config sdpconnect.ConfigurationServiceClient
+}
+
+type signalConfigResourceModel struct {
+	ID                types.String          `tfsdk:"id"`
+	YAML              signalConfigYAMLValue `tfsdk:"yaml"`
+	RoutineChanges    types.Object          `tfsdk:"routine_changes"`
+	PrimaryBranchName types.String          `tfsdk:"primary_branch_name"`
+	CheckRunMode      types.String          `tfsdk:"check_run_mode"`
+	CheckRunsEnabled  types.Bool            `tfsdk:"check_runs_enabled"`
+}
+
+type routineChangesModel struct {
+	Sensitivity    types.Float64 `tfsdk:"sensitivity"`
+	DurationInDays types.Float64 `tfsdk:"duration_in_days"`
+	EventsPerDay   types.Float64 `tfsdk:"events_per_day"`
+}
+
+func NewSignalConfigResource() resource.Resource {
+	return &signalConfigResource{}
+}
+
+func (r *signalConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_signal_config"
+}
+
+func (r *signalConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the signal settings of the Overmind account: routine change detection, the GitHub " +
+			"organisation profile and GitHub check runs. There is one per account, so declare at most one of these " +
+			"resources. Settings can be given either as a yaml document, in the same format as the signal config " +
+			"file used by the Overmind CLI, or as routine_changes and primary_branch_name. Settings that are not " +
+			"configured are left as they are. Destroying the resource leaves the settings in place.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Name of the Overmind account the settings belong to.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"yaml": schema.StringAttribute{
+				Description: "Signal config file contents, with routine_changes_config and/or github_organisation_profile " +
+					"sections. Conflicts with routine_changes and primary_branch_name. Formatting changes do not cause a diff.",
+				CustomType: signalConfigYAMLType{},
+				Optional:   true,
+				Computed:   true,
+			},
+			"routine_changes": schema.SingleNestedAttribute{
+				Description: "Thresholds for detecting routine changes. Settings made in the Overmind UI in weeks or " +
+					"months are converted to days, taking a month as 30 days.",
+				Optional: true,
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"sensitivity": schema.Float64Attribute{
+						Description: "How responsive detection is to smaller changes. Must be 0 or higher.",
+						Required:    true,
+					},
+					"duration_in_days": schema.Float64Attribute{
+						Description: "Number of days over which routine changes are considered. Must be at least 1.",
+						Required:    true,
+					},
+					"events_per_day": schema.Float64Attribute{
+						Description: "Number of changes per day that are considered routine. Must be at least 1.",
+						Required:    true,
+					},
+				},
+			},
+			"primary_branch_name": schema.StringAttribute{
+				Description: "Primary branch of the GitHub organisation's repositories, e.g. \"main\".",
+				Optional:    true,
+				Computed:    true,
+			},
+			"check_run_mode": schema.StringAttribute{
+				Description: fmt.Sprintf("When GitHub check runs fail: one of %s.", strings.Join(checkRunModes(), ", ")),
+				Optional:    true,
+				Computed:    true,
+			},
+			"check_runs_enabled": schema.BoolAttribute{
+				Description: "Whether Overmind reports GitHub check runs for pull requests.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *signalConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config signalConfigResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.YAML.IsNull() {
+		for _, attr := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"routine_changes", config.RoutineChanges},
+			{"primary_branch_name", config.PrimaryBranchName},
+		} {
+			if !attr.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(attr.name), "Conflicting signal config",
+					fmt.Sprintf("%s cannot be set together with yaml. Put it in the yaml document instead.", attr.name))
+			}
+		}
+	}
+
+	if !config.YAML.IsNull() && !config.YAML.IsUnknown() {
+		if _, err := sdp.YamlStringToSignalConfig(config.YAML.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("yaml"), "Invalid signal config YAML", err.Error())
+		}
+	}
+
+	if !config.RoutineChanges.IsNull() && !config.RoutineChanges.IsUnknown() {
+		// Validate through the same parser as yaml so both forms accept the
+		// same values.
+		routine, diags := routineChangesYAML(ctx, config.RoutineChanges)
+		resp.Diagnostics.Append(diags...)
+		if routine != nil {
+			doc, err := yaml.Marshal(sdp.SignalConfigYAML{RoutineChangesConfig: routine})
+			if err == nil {
+				_, err = sdp.YamlStringToSignalConfig(string(doc))
+			}
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("routine_changes"), "Invalid routine changes config", err.Error())
+			}
+		}
+
+		// Overmind stores these as float32, so values with more precision
+		// would be read back differently and fail the apply.
+		for _, name := range []string{"sensitivity", "duration_in_days", "events_per_day"} {
+			v, ok := config.RoutineChanges.Attributes()[name].(types.Float64)
+			if !ok || v.IsNull() || v.IsUnknown() {
+				continue
+			}
+			if stored := float32ToFloat64(float32(v.ValueFloat64())); stored != v.ValueFloat64() {
+				resp.Diagnostics.AddAttributeError(path.Root("routine_changes").AtName(name), "Imprecise routine changes value",
+					fmt.Sprintf("%s is stored with single precision, so %v would be read back as %v. Set it to %v instead.",
+						name, v.ValueFloat64(), stored, stored))
+			}
+		}
+	}
+
+	if !config.CheckRunMode.IsNull() && !config.CheckRunMode.IsUnknown() {
+		if _, ok := sdp.CheckRunMode_value[config.CheckRunMode.ValueString()]; !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("check_run_mode"), "Invalid check run mode",
+				fmt.Sprintf("check_run_mode must be one of %s, got %q.",
+					strings.Join(checkRunModes(), ", "), config.CheckRunMode.ValueString()))
+		}
+	}
+}
+
+func (r *signalConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+	r.config = clients.Configuration
+}
+
+func (r *signalConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "SignalConfig Create")
+	defer span.End()
+
+	var plan signalConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accountResp, err := r.mgmt.GetAccount(ctx, connect.NewRequest(&sdp.GetAccountRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get account", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetAccount failed")
+		return
+	}
+	plan.ID = types.StringValue(accountResp.Msg.GetAccount().GetProperties().GetName())
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "apply failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *signalConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "SignalConfig Read")
+	defer span.End()
+
+	var state signalConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", state.ID.ValueString()))
+
+	getResp, err := r.config.GetSignalConfig(ctx, connect.NewRequest(&sdp.GetSignalConfigRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read signal config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSignalConfig failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setSignalConfig(ctx, getResp.Msg.GetConfig())...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *signalConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "SignalConfig Update")
+	defer span.End()
+
+	var plan signalConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.account.name", plan.ID.ValueString()))
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "apply failed")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the settings from state. There are no documented
+// defaults to restore, and clearing routine change detection would change
+// how every future change is scored.
+func (r *signalConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, _ *resource.DeleteResponse) {
+	_, span := tracing.Tracer().Start(ctx, "SignalConfig Delete")
+	defer span.End()
+}
+
+func (r *signalConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "SignalConfig Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.account.name", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply merges the planned settings into the current ones, so settings that
+// are not configured (or cannot be, such as hourly scores) are kept, then
+// sends them to the API and records the result in the model.
+func (r *signalConfigResource) apply(ctx context.Context, plan *signalConfigResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	getResp, err := r.config.GetSignalConfig(ctx, connect.NewRequest(&sdp.GetSignalConfigRequest{}))
+	if err != nil {
+		diags.AddError("Failed to read signal config", err.Error())
+		return diags
+	}
+	config := proto.Clone(getResp.Msg.GetConfig()).(*sdp.SignalConfig)
+	if config == nil {
+		config = &sdp.SignalConfig{}
+	}
+
+	var routine *sdp.RoutineChangesConfig
+	var primaryBranchName *string
+	if !plan.YAML.IsNull() && !plan.YAML.IsUnknown() {
+		file, err := sdp.YamlStringToSignalConfig(plan.YAML.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("yaml"), "Invalid signal config YAML", err.Error())
+			return diags
+		}
+		routine = file.RoutineChangesConfig
+		if file.GithubOrganisationProfile != nil {
+			primaryBranchName = &file.GithubOrganisationProfile.PrimaryBranchName
+		}
+	} else {
+		if !plan.RoutineChanges.IsNull() && !plan.RoutineChanges.IsUnknown() {
+			routineYAML, d := routineChangesYAML(ctx, plan.RoutineChanges)
+			diags.Append(d...)
+			if diags.HasError() {
+				return diags
+			}
+			routine = &sdp.RoutineChangesConfig{
+				Sensitivity:   routineYAML.Sensitivity,
+				EventsPer:     routineYAML.EventsPerDay,
+				EventsPerUnit: sdp.RoutineChangesConfig_DAYS,
+				Duration:      routineYAML.DurationInDays,
+				DurationUnit:  sdp.RoutineChangesConfig_DAYS,
+			}
+		}
+		if !plan.PrimaryBranchName.IsNull() && !plan.PrimaryBranchName.IsUnknown() {
+			primaryBranchName = plan.PrimaryBranchName.ValueStringPointer()
+		}
+	}
+
+	if routine != nil {
+		config.RoutineChangesConfig = routine
+	}
+	if primaryBranchName != nil {
+		if config.GithubOrganisationProfile == nil {
+			config.GithubOrganisationProfile = &sdp.GithubOrganisationProfile{}
+		}
+		config.GithubOrganisationProfile.PrimaryBranchName = *primaryBranchName
+	}
+	if !plan.CheckRunMode.IsNull() && !plan.CheckRunMode.IsUnknown() {
+		config.CheckRunMode = sdp.CheckRunMode(sdp.CheckRunMode_value[plan.CheckRunMode.ValueString()])
+	}
+	if !plan.CheckRunsEnabled.IsNull() && !plan.CheckRunsEnabled.IsUnknown() {
+		config.CheckRunsEnabled = plan.CheckRunsEnabled.ValueBool()
+	}
+
+	updateResp, err := r.config.UpdateSignalConfig(ctx, connect.NewRequest(&sdp.UpdateSignalConfigRequest{
+		Config: config,
+	}))
+	if err != nil {
+		diags.AddError("Failed to update signal config", err.Error())
+		return diags
+	}
+
+	diags.Append(plan.setSignalConfig(ctx, updateResp.Msg.GetConfig())...)
+	return diags
+}
+
+// setSignalConfig copies the API representation of the settings into the
+// model. yaml is rendered in a canonical form; when the model already holds
+// a document, only the sections it contains are rendered so that it stays
+// semantically equal to the configuration.
+func (m *signalConfigResourceModel) setSignalConfig(ctx context.Context, config *sdp.SignalConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	doc := sdp.SignalConfigYAML{}
+	if routine := config.GetRoutineChangesConfig(); routine != nil {
+		doc.RoutineChangesConfig = &sdp.RoutineChangesYAML{
+			Sensitivity:    routine.GetSensitivity(),
+			DurationInDays: routine.GetDuration() * routineChangesUnitDays(routine.GetDurationUnit()),
+			EventsPerDay:   routine.GetEventsPer() / routineChangesUnitDays(routine.GetEventsPerUnit()),
+		}
+		m.RoutineChanges, diags = types.ObjectValue(routineChangesAttrTypes, map[string]attr.Value{
+			"sensitivity":      types.Float64Value(float32ToFloat64(doc.RoutineChangesConfig.Sensitivity)),
+			"duration_in_days": types.Float64Value(float32ToFloat64(doc.RoutineChangesConfig.DurationInDays)),
+			"events_per_day":   types.Float64Value(float32ToFloat64(doc.RoutineChangesConfig.EventsPerDay)),
+		})
+	} else {
+		m.RoutineChanges = types.ObjectNull(routineChangesAttrTypes)
+	}
+
+	if profile := config.GetGithubOrganisationProfile(); profile != nil {
+		doc.GithubOrganisationProfile = &sdp.GithubOrganisationYAML{PrimaryBranchName: profile.GetPrimaryBranchName()}
+		m.PrimaryBranchName = stringOrNull(profile.GetPrimaryBranchName())
+	} else {
+		m.PrimaryBranchName = types.StringNull()
+	}
+
+	m.CheckRunMode = types.StringValue(config.GetCheckRunMode().String())
+	m.CheckRunsEnabled = types.BoolValue(config.GetCheckRunsEnabled())
+
+	if !m.YAML.IsNull() && !m.YAML.IsUnknown() {
+		if current, err := sdp.YamlStringToSignalConfig(m.YAML.ValueString()); err == nil {
+			if current.RoutineChangesConfig == nil {
+				doc.RoutineChangesConfig = nil
+			}
+			if current.GithubOrganisationProfile == nil {
+				doc.GithubOrganisationProfile = nil
+			}
+		}
+	}
+
+	if doc.RoutineChangesConfig == nil && doc.GithubOrganisationProfile == nil {
+		m.YAML = signalConfigYAMLNull()
+		return diags
+	}
+	rendered, err := yaml.Marshal(doc)
+	if err != nil {
+		diags.AddError("Failed to render signal config YAML", err.Error())
+		m.YAML = signalConfigYAMLUnknown()
+		return diags
+	}
+	m.YAML = newSignalConfigYAMLValue(string(rendered))
+	return diags
+}
+
+// routineChangesYAML converts the routine_changes attribute into the YAML
+// form understood by sdp.YamlStringToSignalConfig.
+func routineChangesYAML(ctx context.Context, obj types.Object) (*sdp.RoutineChangesYAML, diag.Diagnostics) {
+	var routine routineChangesModel
+	diags := obj.As(ctx, &routine, basetypes.ObjectAsOptions{})
+	if diags.HasError() || routine.Sensitivity.IsUnknown() || routine.DurationInDays.IsUnknown() || routine.EventsPerDay.IsUnknown() {
+		return nil, diags
+	}
+	return &sdp.RoutineChangesYAML{
+		Sensitivity:    float32(routine.Sensitivity.ValueFloat64()),
+		DurationInDays: float32(routine.DurationInDays.ValueFloat64()),
+		EventsPerDay:   float32(routine.EventsPerDay.ValueFloat64()),
+	}, diags
+}
+
+// routineChangesUnitDays is the number of days in a routine changes duration
+// unit.
+func routineChangesUnitDays(unit sdp.RoutineChangesConfig_DurationUnit) float32 {
+	switch unit {
+	case sdp.RoutineChangesConfig_WEEKS:
+		return 7
+	case sdp.RoutineChangesConfig_MONTHS:
+		return 30
+	default:
+		return 1
+	}
+}
+
+// float32ToFloat64 widens f using its shortest decimal representation, so
+// that 0.1 stays 0.1 instead of becoming 0.10000000149011612.
+func float32ToFloat64(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return v
+}
+
+// checkRunModes returns the names of all check run modes in enum order.
+func checkRunModes() []string {
+	modes := make([]string, 0, len(sdp.CheckRunMode_name))
+	for _, name := range sdp.CheckRunMode_name {
+		modes = append(modes, name)
+	}
+	slices.SortFunc(modes, func(a, b string) int {
+		return int(sdp.CheckRunMode_value[a] - sdp.CheckRunMode_value[b])
+	})
+	return modes
+}
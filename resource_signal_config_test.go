@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"connectrpc.com/connect"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"google.golang.org/protobuf/proto"
+)
+
+func (m *mockConfigHandler) GetSignalConfig(_ context.Context, _ *connect.Request[sdp.GetSignalConfigRequest]) (*connect.Response[sdp.GetSignalConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return connect.NewResponse(&sdp.GetSignalConfigResponse{Config: proto.Clone(m.signalConfig).(*sdp.SignalConfig)}), nil
+}
+
+func (m *mockConfigHandler) UpdateSignalConfig(_ context.Context, req *connect.Request[sdp.UpdateSignalConfigRequest]) (*connect.Response[sdp.UpdateSignalConfigResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.signalConfig = proto.Clone(req.Msg.GetConfig()).(*sdp.SignalConfig)
+	return connect.NewResponse(&sdp.UpdateSignalConfigResponse{Config: proto.Clone(m.signalConfig).(*sdp.SignalConfig)}), nil
+}
+
+func TestSignalConfigResource_YAML(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	// Hourly scores cannot be managed from Terraform and must be kept.
+	mocks.config.signalConfig = &sdp.SignalConfig{
+		GithubOrganisationProfile: &sdp.GithubOrganisationProfile{PrimaryBranchName: "master", HourlyScores: []float64{1, 2, 3}},
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  yaml = <<-EOT
+    routine_changes_config:
+      sensitivity: 0.1
+      duration_in_days: 14
+      events_per_day: 3
+    github_organisation_profile:
+      primary_branch_name: main
+  EOT
+  check_run_mode     = "CHECK_RUN_MODE_FAIL_HIGH_SEVERITY"
+  check_runs_enabled = true
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_signal_config.this", "routine_changes.sensitivity", "0.1"),
+					tfresource.TestCheckResourceAttr("overmind_signal_config.this", "routine_changes.duration_in_days", "14"),
+					tfresource.TestCheckResourceAttr("overmind_signal_config.this", "primary_branch_name", "main"),
+					tfresource.TestCheckResourceAttr("overmind_signal_config.this", "check_runs_enabled", "true"),
+					func(_ *terraform.State) error {
+						if got := mocks.config.signalConfig.GetGithubOrganisationProfile().GetHourlyScores(); len(got) != 3 {
+							return fmt.Errorf("hourly scores not kept: %v", got)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Reformatting and reordering the document is not a change.
+				Config: `resource "overmind_signal_config" "this" {
+  yaml = <<-EOT
+    github_organisation_profile: {primary_branch_name: main}
+    routine_changes_config:
+        events_per_day: 3
+        duration_in_days: 14.0
+        sensitivity: 0.1
+  EOT
+  check_run_mode     = "CHECK_RUN_MODE_FAIL_HIGH_SEVERITY"
+  check_runs_enabled = true
+}`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestSignalConfigResource_Structured(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  routine_changes = {
+    sensitivity      = 0.5
+    duration_in_days = 30
+    events_per_day   = 2
+  }
+  primary_branch_name = "main"
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_signal_config.this", "check_run_mode", "CHECK_RUN_MODE_REPORT_ONLY"),
+					tfresource.TestMatchResourceAttr("overmind_signal_config.this", "yaml", regexp.MustCompile(`primary_branch_name: main`)),
+				),
+			},
+			{
+				ResourceName:      "overmind_signal_config.this",
+				ImportState:       true,
+				ImportStateId:     "8d7e4f5a-1b2c-4d3e-9f80-a1b2c3d4e5f6",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSignalConfigResource_Validation(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  yaml = "routine_changes_config: {sensitivity: 1, duration_in_days: 0, events_per_day: 1}"
+}`,
+				ExpectError: regexp.MustCompile(`duration_in_days must be greater than 1`),
+			},
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  routine_changes = {
+    sensitivity      = -1
+    duration_in_days = 7
+    events_per_day   = 1
+  }
+}`,
+				ExpectError: regexp.MustCompile(`sensitivity must be 0 or higher`),
+			},
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  routine_changes = {
+    sensitivity      = 0.123456789
+    duration_in_days = 7
+    events_per_day   = 1
+  }
+}`,
+				ExpectError: regexp.MustCompile(`(?s)Imprecise routine changes value.*Set it to 0.12345679 instead`),
+			},
+			{
+				Config: `resource "overmind_signal_config" "this" {
+  yaml                = "github_organisation_profile: {primary_branch_name: main}"
+  primary_branch_name = "main"
+}`,
+				ExpectError: regexp.MustCompile(`Conflicting signal config`),
+			},
+		},
+	})
+}
+
+func TestSignalConfigYAML_SemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"github_organisation_profile:\n  primary_branch_name: main\n", "github_organisation_profile: {primary_branch_name: main}", true},
+		{"routine_changes_config: {sensitivity: 0.1, duration_in_days: 7, events_per_day: 1}", "routine_changes_config: {events_per_day: 1.0, duration_in_days: 7, sensitivity: 0.1}", true},
+		{"github_organisation_profile: {primary_branch_name: main}", "github_organisation_profile: {primary_branch_name: master}", false},
+		{"github_organisation_profile: {primary_branch_name: main}", "not: [valid", false},
+	} {
+		got, diags := newSignalConfigYAMLValue(tc.a).StringSemanticEquals(ctx, newSignalConfigYAMLValue(tc.b))
+		if diags.HasError() {
+			t.Fatalf("StringSemanticEquals: %v", diags)
+		}
+		if got != tc.want {
+			t.Errorf("StringSemanticEquals(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
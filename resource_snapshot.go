@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	_ resource.Resource                   = (*snapshotResource)(nil)
+	_ resource.ResourceWithImportState    = (*snapshotResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*snapshotResource)(nil)
+)
+
+type snapshotResource struct {
+	snapshots sdpconnect.SnapshotsServiceClient
+}
+
+type snapshotResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Created     types.String `tfsdk:"created"`
+	ItemCount   types.Int64  `tfsdk:"item_count"`
+	EdgeCount   types.Int64  `tfsdk:"edge_count"`
+	Queries     []queryModel `tfsdk:"query"`
+}
+
+func NewSnapshotResource() resource.Resource {
+	return &snapshotResource{}
+}
+
+func (r *snapshotResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot"
+}
+
+func (r *snapshotResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Takes an Overmind snapshot: the items and edges found by a set of queries, frozen at the time " +
+			"the snapshot is created. Use it to record the state of an environment before a migration, and read it " +
+			"back later with the overmind_snapshot data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Snapshot UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the snapshot.",
+				Required:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "Description of the snapshot.",
+				Optional:    true,
+			},
+			"created": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of when the snapshot was taken.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"item_count": schema.Int64Attribute{
+				Description: "Number of items in the snapshot.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"edge_count": schema.Int64Attribute{
+				Description: "Number of edges between items in the snapshot.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"query": queryBlock("Queries whose results are captured in the snapshot. Changing them takes a new snapshot.",
+				listplanmodifier.RequiresReplace()),
+		},
+	}
+}
+
+func (r *snapshotResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config snapshotResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateQueries(config.Queries, path.Root("query"))...)
+}
+
+func (r *snapshotResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.snapshots = clients.Snapshots
+}
+
+func (r *snapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot Create")
+	defer span.End()
+
+	var plan snapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.snapshot.name", plan.Name.ValueString()))
+
+	createResp, err := r.snapshots.CreateSnapshot(ctx, connect.NewRequest(&sdp.CreateSnapshotRequest{
+		Properties: &sdp.SnapshotProperties{
+			Name:        plan.Name.ValueString(),
+			Description: plan.Description.ValueString(),
+			Queries:     queriesToSDP(plan.Queries),
+		},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateSnapshot failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setSnapshot(createResp.Msg.GetSnapshot())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("ovm.snapshot.id", plan.ID.ValueString()),
+		attribute.Int64("ovm.snapshot.items", plan.ItemCount.ValueInt64()),
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *snapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot Read")
+	defer span.End()
+
+	var state snapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.snapshot.id", state.ID.ValueString()))
+
+	snapshot, err := r.getSnapshot(ctx, state.ID.ValueString())
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.snapshot.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSnapshot failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setSnapshot(snapshot)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update renames the snapshot. Everything else forces a new snapshot, so the
+// captured queries, items and edges are sent back unchanged.
+func (r *snapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot Update")
+	defer span.End()
+
+	var plan snapshotResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state snapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.snapshot.id", state.ID.ValueString()),
+		attribute.String("ovm.snapshot.name", plan.Name.ValueString()),
+	)
+
+	current, err := r.getSnapshot(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSnapshot failed")
+		return
+	}
+
+	props := &sdp.SnapshotProperties{}
+	if current.GetProperties() != nil {
+		props = proto.Clone(current.GetProperties()).(*sdp.SnapshotProperties)
+	}
+	props.Name = plan.Name.ValueString()
+	props.Description = plan.Description.ValueString()
+
+	updateResp, err := r.snapshots.UpdateSnapshot(ctx, connect.NewRequest(&sdp.UpdateSnapshotRequest{
+		UUID:       current.GetMetadata().GetUUID(),
+		Properties: props,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateSnapshot failed")
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setSnapshot(updateResp.Msg.GetSnapshot())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *snapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot Delete")
+	defer span.End()
+
+	var state snapshotResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.snapshot.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid snapshot ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.snapshots.DeleteSnapshot(ctx, connect.NewRequest(&sdp.DeleteSnapshotRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.snapshot.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete snapshot", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteSnapshot failed")
+	}
+}
+
+func (r *snapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Snapshot Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.snapshot.id", req.ID))
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *snapshotResource) getSnapshot(ctx context.Context, id string) (*sdp.Snapshot, error) {
+	uuidBytes, err := uuidToBytes(id)
+	if err != nil {
+		return nil, err
+	}
+	getResp, err := r.snapshots.GetSnapshot(ctx, connect.NewRequest(&sdp.GetSnapshotRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return getResp.Msg.GetSnapshot(), nil
+}
+
+// setSnapshot copies the API representation of the snapshot into the model.
+func (m *snapshotResourceModel) setSnapshot(snapshot *sdp.Snapshot) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	id, err := uuid.FromBytes(snapshot.GetMetadata().GetUUID())
+	if err != nil {
+		diags.AddError("Failed to parse snapshot UUID", err.Error())
+		return diags
+	}
+
+	props := snapshot.GetProperties()
+	m.ID = types.StringValue(id.String())
+	m.Name = types.StringValue(props.GetName())
+	m.Description = stringOrNull(props.GetDescription())
+	m.Created = timestampString(snapshot.GetMetadata().GetCreated())
+	m.ItemCount = types.Int64Value(int64(len(props.GetItems())))
+	m.EdgeCount = types.Int64Value(int64(len(props.GetEdges())))
+	m.Queries = nil
+	if len(props.GetQueries()) > 0 {
+		m.Queries = queryModelsFromSDP(props.GetQueries())
+	}
+	return diags
+}
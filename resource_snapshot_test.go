@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- mock SnapshotsService handler ---
+
+type mockSnapshotsHandler struct {
+	sdpconnect.UnimplementedSnapshotsServiceHandler
+	mu        sync.Mutex
+	snapshots map[string]*sdp.Snapshot
+}
+
+func newMockSnapshotsHandler() *mockSnapshotsHandler {
+	return &mockSnapshotsHandler{snapshots: make(map[string]*sdp.Snapshot)}
+}
+
+func (m *mockSnapshotsHandler) lookup(b []byte) (*sdp.Snapshot, error) {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	snapshot, ok := m.snapshots[id.String()]
+	if !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	return snapshot, nil
+}
+
+// CreateSnapshot captures one item per query, each linked to the next.
+func (m *mockSnapshotsHandler) CreateSnapshot(_ context.Context, req *connect.Request[sdp.CreateSnapshotRequest]) (*connect.Response[sdp.CreateSnapshotResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	props := req.Msg.GetProperties()
+	for i, q := range props.GetQueries() {
+		attrs, _ := structpb.NewStruct(map[string]any{"name": q.GetQuery(), "index": i})
+		item := &sdp.Item{
+			Type:            q.GetType(),
+			Scope:           q.GetScope(),
+			UniqueAttribute: "name",
+			Attributes:      &sdp.ItemAttributes{AttrStruct: attrs},
+		}
+		if i > 0 {
+			props.Edges = append(props.Edges, &sdp.Edge{From: props.GetItems()[i-1].Reference(), To: item.Reference()})
+		}
+		props.Items = append(props.Items, item)
+	}
+	id := uuid.New()
+	snapshot := &sdp.Snapshot{
+		Metadata:   &sdp.SnapshotMetadata{UUID: id[:], Created: timestamppb.Now()},
+		Properties: props,
+	}
+	m.snapshots[id.String()] = snapshot
+	return connect.NewResponse(&sdp.CreateSnapshotResponse{Snapshot: snapshot}), nil
+}
+
+func (m *mockSnapshotsHandler) GetSnapshot(_ context.Context, req *connect.Request[sdp.GetSnapshotRequest]) (*connect.Response[sdp.GetSnapshotResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&sdp.GetSnapshotResponse{Snapshot: snapshot}), nil
+}
+
+func (m *mockSnapshotsHandler) UpdateSnapshot(_ context.Context, req *connect.Request[sdp.UpdateSnapshotRequest]) (*connect.Response[sdp.UpdateSnapshotResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Properties = req.Msg.GetProperties()
+	return connect.NewResponse(&sdp.UpdateSnapshotResponse{Snapshot: snapshot}), nil
+}
+
+func (m *mockSnapshotsHandler) DeleteSnapshot(_ context.Context, req *connect.Request[sdp.DeleteSnapshotRequest]) (*connect.Response[sdp.DeleteSnapshotResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot, err := m.lookup(req.Msg.GetUUID())
+	if err != nil {
+		return nil, err
+	}
+	delete(m.snapshots, uuid.UUID(snapshot.GetMetadata().GetUUID()).String())
+	return connect.NewResponse(&sdp.DeleteSnapshotResponse{}), nil
+}
+
+// ListSnapshots returns snapshots without their items and edges, like the
+// real API.
+func (m *mockSnapshotsHandler) ListSnapshots(_ context.Context, _ *connect.Request[sdp.ListSnapshotsRequest]) (*connect.Response[sdp.ListSnapshotResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshots := make([]*sdp.Snapshot, 0, len(m.snapshots))
+	for _, snapshot := range m.snapshots {
+		snapshots = append(snapshots, &sdp.Snapshot{
+			Metadata: snapshot.GetMetadata(),
+			Properties: &sdp.SnapshotProperties{
+				Name:        snapshot.GetProperties().GetName(),
+				Description: snapshot.GetProperties().GetDescription(),
+			},
+		})
+	}
+	return connect.NewResponse(&sdp.ListSnapshotResponse{Snapshots: snapshots}), nil
+}
+
+// --- tests ---
+
+func testAccSnapshotConfig(name string) string {
+	return `resource "overmind_snapshot" "pre_migration" {
+  name = "` + name + `"
+
+  query {
+    type  = "rds-db-cluster"
+    query = "orders"
+    scope = "123456789012.eu-west-1"
+  }
+
+  query {
+    type  = "ec2-security-group"
+    query = "sg-0123456789"
+    scope = "123456789012.eu-west-1"
+  }
+}`
+}
+
+func TestSnapshotResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	var id string
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if len(mocks.snapshots.snapshots) != 0 {
+				return fmt.Errorf("expected all snapshots to be deleted, %d remain", len(mocks.snapshots.snapshots))
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccSnapshotConfig("before orders migration"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_snapshot.pre_migration", "item_count", "2"),
+					tfresource.TestCheckResourceAttr("overmind_snapshot.pre_migration", "edge_count", "1"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["overmind_snapshot.pre_migration"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// Renaming keeps the snapshot and what it captured.
+				Config: testAccSnapshotConfig("orders migration baseline"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPtr("overmind_snapshot.pre_migration", "id", &id),
+					tfresource.TestCheckResourceAttr("overmind_snapshot.pre_migration", "item_count", "2"),
+				),
+			},
+			{
+				Config: testAccSnapshotConfig("orders migration baseline") + `
+
+data "overmind_snapshot" "by_name" {
+  name = overmind_snapshot.pre_migration.name
+}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttrPtr("data.overmind_snapshot.by_name", "id", &id),
+					tfresource.TestCheckResourceAttr("data.overmind_snapshot.by_name", "items.#", "2"),
+					tfresource.TestCheckResourceAttr("data.overmind_snapshot.by_name", "items.0.globally_unique_name",
+						"123456789012.eu-west-1.rds-db-cluster.orders"),
+					tfresource.TestCheckResourceAttr("data.overmind_snapshot.by_name", "items.0.attributes", `{"index":0,"name":"orders"}`),
+					tfresource.TestCheckResourceAttr("data.overmind_snapshot.by_name", "edges.0.to",
+						"123456789012.eu-west-1.ec2-security-group.sg-0123456789"),
+				),
+			},
+			{
+				ResourceName:      "overmind_snapshot.pre_migration",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestSnapshotDataSource_Lookup(t *testing.T) {
+	serverURL := startTestServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config:      `data "overmind_snapshot" "missing" {}`,
+				ExpectError: regexp.MustCompile(`Exactly one of id and name must be set`),
+			},
+			{
+				Config:      `data "overmind_snapshot" "missing" { name = "nope" }`,
+				ExpectError: regexp.MustCompile(`No snapshot is named "nope"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	_ resource.Resource                   = (*sourceResource)(nil)
+	_ resource.ResourceWithIdentity       = (*sourceResource)(nil)
+	_ resource.ResourceWithImportState    = (*sourceResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*sourceResource)(nil)
+)
+
+type sourceResource struct {
+	mgmt sdpconnect.ManagementServiceClient
+}
+
+type sourceResourceModel struct {
+	ID               types.String  `tfsdk:"id"`
+	Name             types.String  `tfsdk:"name"`
+	Type             types.String  `tfsdk:"type"`
+	Config           types.Dynamic `tfsdk:"config"`
+	AdditionalConfig types.Dynamic `tfsdk:"additional_config"`
+	Health           types.Object  `tfsdk:"health"`
+}
+
+// sourceIdentityModel is the resource identity shared by every source
+// resource. A source is identified by its UUID alone.
+type sourceIdentityModel struct {
+	ID types.String `tfsdk:"id"`
+}
+
+func sourceIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"id": identityschema.StringAttribute{
+				Description:       "Source UUID.",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func NewSourceResource() resource.Resource {
+	return &sourceResource{}
+}
+
+func (r *sourceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_source"
+}
+
+func (r *sourceResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = sourceIdentitySchema()
+}
+
+func (r *sourceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an Overmind infrastructure source of any type (e.g. gcp, azure, k8s). " +
+			"Prefer a dedicated resource such as overmind_aws_source where one exists.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Source UUID assigned by the Overmind API.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Human-readable name for this source.",
+				Required:    true,
+			},
+			"type": schema.StringAttribute{
+				Description: "Source type, e.g. \"gcp\", \"azure\" or \"k8s\". Changing this forces a new source.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"config": schema.DynamicAttribute{
+				Description: "Source configuration object. Keys and values are passed to the source as-is, " +
+					"nested objects and lists are supported.",
+				Optional: true,
+			},
+			"additional_config": schema.DynamicAttribute{
+				Description: "Additional source configuration object, passed to the source alongside config.",
+				Optional:    true,
+			},
+			"health": sourceHealthSchemaAttribute(),
+		},
+	}
+}
+
+func (r *sourceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config sourceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for attrName, v := range map[string]types.Dynamic{
+		"config":            config.Config,
+		"additional_config": config.AdditionalConfig,
+	} {
+		if v.IsNull() || v.IsUnknown() || v.IsUnderlyingValueNull() || v.IsUnderlyingValueUnknown() {
+			continue
+		}
+		switch v.UnderlyingValue().(type) {
+		case basetypes.ObjectValue, basetypes.MapValue:
+		default:
+			resp.Diagnostics.AddAttributeError(path.Root(attrName), "Invalid source configuration",
+				fmt.Sprintf("%s must be an object, got %s.", attrName, v.UnderlyingValue().Type(ctx)))
+		}
+	}
+}
+
+func (r *sourceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.mgmt = clients.Management
+}
+
+func (r *sourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source Create")
+	defer span.End()
+
+	var plan sourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.source.name", plan.Name.ValueString()),
+		attribute.String("ovm.source.type", plan.Type.ValueString()),
+	)
+
+	props, err := plan.sourceProperties(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build source config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "config build failed")
+		return
+	}
+
+	createResp, err := r.mgmt.CreateSource(ctx, connect.NewRequest(&sdp.CreateSourceRequest{
+		Properties: props,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateSource failed")
+		return
+	}
+
+	source := createResp.Msg.GetSource()
+	sourceUUID, err := uuid.FromBytes(source.GetMetadata().GetUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse source UUID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UUID parse failed")
+		return
+	}
+
+	plan.ID = types.StringValue(sourceUUID.String())
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: plan.ID})...)
+
+	span.SetAttributes(attribute.String("ovm.source.id", sourceUUID.String()))
+
+	health, diags := readSourceHealth(ctx, r.mgmt, source.GetMetadata().GetUUID())
+	resp.Diagnostics.Append(diags...)
+	plan.Health = health
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *sourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source Read")
+	defer span.End()
+
+	var state sourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.source.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	getResp, err := r.mgmt.GetSource(ctx, connect.NewRequest(&sdp.GetSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.source.removed", true))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "GetSource failed")
+		return
+	}
+
+	resp.Diagnostics.Append(state.setSourceProperties(ctx, getResp.Msg.GetSource().GetProperties())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	health, diags := readSourceHealth(ctx, r.mgmt, uuidBytes)
+	resp.Diagnostics.Append(diags...)
+	state.Health = health
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: state.ID})...)
+}
+
+func (r *sourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source Update")
+	defer span.End()
+
+	var plan sourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state sourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("ovm.source.id", state.ID.ValueString()),
+		attribute.String("ovm.source.name", plan.Name.ValueString()),
+		attribute.String("ovm.source.type", plan.Type.ValueString()),
+	)
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	props, err := plan.sourceProperties(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build source config", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "config build failed")
+		return
+	}
+
+	_, err = r.mgmt.UpdateSource(ctx, connect.NewRequest(&sdp.UpdateSourceRequest{
+		UUID:       uuidBytes,
+		Properties: props,
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UpdateSource failed")
+		return
+	}
+
+	health, diags := readSourceHealth(ctx, r.mgmt, uuidBytes)
+	resp.Diagnostics.Append(diags...)
+	plan.Health = health
+
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *sourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source Delete")
+	defer span.End()
+
+	var state sourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.source.id", state.ID.ValueString()))
+
+	uuidBytes, err := uuidToBytes(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source ID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return
+	}
+
+	_, err = r.mgmt.DeleteSource(ctx, connect.NewRequest(&sdp.DeleteSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.source.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to delete source", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "DeleteSource failed")
+	}
+}
+
+func (r *sourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "Source Import")
+	defer span.End()
+
+	importSourceOfType(ctx, r.mgmt, "", req, resp)
+}
+
+// sourceProperties builds the API representation of the planned source.
+func (m *sourceResourceModel) sourceProperties(ctx context.Context) (*sdp.SourceProperties, error) {
+	config, err := structFromDynamic(ctx, m.Config)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	additionalConfig, err := structFromDynamic(ctx, m.AdditionalConfig)
+	if err != nil {
+		return nil, fmt.Errorf("additional_config: %w", err)
+	}
+	return &sdp.SourceProperties{
+		DescriptiveName:  m.Name.ValueString(),
+		Type:             m.Type.ValueString(),
+		Config:           config,
+		AdditionalConfig: additionalConfig,
+	}, nil
+}
+
+// setSourceProperties copies the API representation of the source into the
+// model. Credentials in sensitiveSourceConfigKeys are not copied from the API,
+// so that reading, importing or listing a source never writes them to state or
+// generated configuration; a credential already in config keeps its value so
+// that it does not show up as drift.
+func (m *sourceResourceModel) setSourceProperties(ctx context.Context, props *sdp.SourceProperties) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.Name = types.StringValue(props.GetDescriptiveName())
+	m.Type = types.StringValue(props.GetType())
+
+	prior, err := structFromDynamic(ctx, m.Config)
+	if err != nil {
+		diags.AddAttributeError(path.Root("config"), "Invalid source config", err.Error())
+		return diags
+	}
+	config, d := dynamicFromStruct(ctx, withoutSensitiveConfig(props.GetConfig(), prior))
+	diags.Append(d...)
+	m.Config = config
+
+	additionalConfig, d := dynamicFromStruct(ctx, props.GetAdditionalConfig())
+	diags.Append(d...)
+	m.AdditionalConfig = additionalConfig
+
+	return diags
+}
+
+// withoutSensitiveConfig returns a copy of config with the keys in
+// sensitiveSourceConfigKeys replaced by their value in prior, or removed if
+// prior does not set them.
+func withoutSensitiveConfig(config, prior *structpb.Struct) *structpb.Struct {
+	if config == nil {
+		return nil
+	}
+	fields := maps.Clone(config.GetFields())
+	for _, k := range sensitiveSourceConfigKeys {
+		delete(fields, k)
+		if v, ok := prior.GetFields()[k]; ok {
+			fields[k] = v
+		}
+	}
+	return &structpb.Struct{Fields: fields}
+}
+
+// sourceImportNamePrefix marks an import ID as a source name rather than a
+// UUID, e.g. `terraform import overmind_aws_source.prod name:prod-aws`.
+const sourceImportNamePrefix = "name:"
+
+// importSourceOfType imports a source by UUID, or by name when the import ID
+// has the "name:" prefix. It checks that the source exists and, unless
+// sourceType is empty, has the expected SourceProperties.Type, so that
+// mistakes such as a typo or importing an AWS source into a GCP resource fail
+// at import time rather than on the next plan.
+func importSourceOfType(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceType string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	span := trace.SpanFromContext(ctx)
+
+	importID, diags := sourceImportID(ctx, req)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var source *sdp.Source
+	if name, ok := strings.CutPrefix(importID, sourceImportNamePrefix); ok {
+		span.SetAttributes(attribute.String("ovm.source.name", name))
+		source, diags = findSourceByName(ctx, mgmt, sourceType, name)
+	} else {
+		span.SetAttributes(attribute.String("ovm.source.id", importID))
+		source, diags = getSourceForImport(ctx, mgmt, importID)
+	}
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		span.SetStatus(codes.Error, "source lookup failed")
+		return
+	}
+
+	sourceUUID, err := uuid.FromBytes(source.GetMetadata().GetUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse source UUID", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "UUID parse failed")
+		return
+	}
+	id := sourceUUID.String()
+	span.SetAttributes(attribute.String("ovm.source.id", id))
+
+	actualType := source.GetProperties().GetType()
+	span.SetAttributes(attribute.String("ovm.source.type", actualType))
+	if sourceType != "" && actualType != sourceType {
+		resp.Diagnostics.AddError("Unexpected source type",
+			fmt.Sprintf("Source %s has type %q, but this resource only manages %q sources.", id, actualType, sourceType))
+		span.SetStatus(codes.Error, "source type mismatch")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, sourceIdentityModel{ID: types.StringValue(id)})...)
+}
+
+func getSourceForImport(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, id string) (*sdp.Source, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	uuidBytes, err := uuidToBytes(id)
+	if err != nil {
+		diags.AddError("Invalid source ID",
+			fmt.Sprintf("%s. To import by name, use an ID of the form %s<name>.", err, sourceImportNamePrefix))
+		return nil, diags
+	}
+
+	getResp, err := mgmt.GetSource(ctx, connect.NewRequest(&sdp.GetSourceRequest{
+		UUID: uuidBytes,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			diags.AddError("Source not found", fmt.Sprintf("No source with UUID %s exists in this account.", id))
+			return nil, diags
+		}
+		diags.AddError("Failed to read source", err.Error())
+		return nil, diags
+	}
+	return getResp.Msg.GetSource(), diags
+}
+
+// findSourceByName returns the only source with the given descriptive name,
+// restricted to sourceType unless it is empty. Names are not unique, so more
+// than one match is an error listing the candidate UUIDs.
+func findSourceByName(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceType, name string) (*sdp.Source, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	listResp, err := mgmt.ListSources(ctx, connect.NewRequest(&sdp.ListSourcesRequest{}))
+	if err != nil {
+		diags.AddError("Failed to list sources", err.Error())
+		return nil, diags
+	}
+
+	var matches []*sdp.Source
+	for _, source := range listResp.Msg.GetSources() {
+		props := source.GetProperties()
+		if props.GetDescriptiveName() != name {
+			continue
+		}
+		if sourceType != "" && props.GetType() != sourceType {
+			continue
+		}
+		matches = append(matches, source)
+	}
+
+	switch len(matches) {
+	case 0:
+		what := "source"
+		if sourceType != "" {
+			what = fmt.Sprintf("%q source", sourceType)
+		}
+		diags.AddError("Source not found", fmt.Sprintf("No %s named %q exists in this account.", what, name))
+		return nil, diags
+	case 1:
+		return matches[0], diags
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, source := range matches {
+		if id, err := uuid.FromBytes(source.GetMetadata().GetUUID()); err == nil {
+			ids = append(ids, id.String())
+		}
+	}
+	slices.Sort(ids)
+	diags.AddError("Ambiguous source name",
+		fmt.Sprintf("%d sources are named %q: %s. Import by UUID instead.", len(matches), name, strings.Join(ids, ", ")))
+	return nil, diags
+}
+
+// sourceImportID returns the UUID being imported, whether it was given as an
+// import ID or through an import block's identity.
+func sourceImportID(ctx context.Context, req resource.ImportStateRequest) (string, diag.Diagnostics) {
+	if req.ID != "" || req.Identity == nil {
+		return req.ID, nil
+	}
+	var identity sourceIdentityModel
+	diags := req.Identity.Get(ctx, &identity)
+	return identity.ID.ValueString(), diags
+}
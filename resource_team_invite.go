@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	_ resource.Resource                = (*teamInviteResource)(nil)
+	_ resource.ResourceWithImportState = (*teamInviteResource)(nil)
+)
+
+type teamInviteResource struct {
+	invites sdpconnect.InviteServiceClient
+}
+
+type teamInviteResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Email         types.String `tfsdk:"email"`
+	ResendTrigger types.String `tfsdk:"resend_trigger"`
+	Status        types.String `tfsdk:"status"`
+}
+
+func NewTeamInviteResource() resource.Resource {
+	return &teamInviteResource{}
+}
+
+func (r *teamInviteResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_team_invite"
+}
+
+func (r *teamInviteResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Invites a user to the Overmind account by email. Destroying the resource revokes the invite.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Email address the invite was sent to.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"email": schema.StringAttribute{
+				Description: "Email address to invite. Changing this revokes the invite and sends a new one.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"resend_trigger": schema.StringAttribute{
+				Description: "Arbitrary value that resends the invite email when changed.",
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Invite status, INVITE_STATUS_INVITED until the user accepts and INVITE_STATUS_ACCEPTED after.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *teamInviteResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*overmindClients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *overmindClients, got %T", req.ProviderData))
+		return
+	}
+	r.invites = clients.Invites
+}
+
+func (r *teamInviteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamInvite Create")
+	defer span.End()
+
+	var plan teamInviteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	email := plan.Email.ValueString()
+	span.SetAttributes(attribute.String("ovm.invite.email", email))
+
+	_, err := r.invites.CreateInvite(ctx, connect.NewRequest(&sdp.CreateInviteRequest{
+		Emails: []string{email},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create invite", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "CreateInvite failed")
+		return
+	}
+
+	invite, err := findInvite(ctx, r.invites, email)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read invite", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListInvites failed")
+		return
+	}
+
+	plan.ID = types.StringValue(email)
+	plan.Status = types.StringValue(sdp.Invite_INVITE_STATUS_INVITED.String())
+	if invite != nil {
+		plan.Status = types.StringValue(invite.GetStatus().String())
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *teamInviteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamInvite Read")
+	defer span.End()
+
+	var state teamInviteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.invite.email", state.ID.ValueString()))
+
+	invite, err := findInvite(ctx, r.invites, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read invite", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "ListInvites failed")
+		return
+	}
+	if invite == nil {
+		span.SetAttributes(attribute.Bool("ovm.invite.removed", true))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Status = types.StringValue(invite.GetStatus().String())
+	span.SetAttributes(attribute.String("ovm.invite.status", state.Status.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *teamInviteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamInvite Update")
+	defer span.End()
+
+	var plan, state teamInviteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.invite.email", state.ID.ValueString()))
+
+	plan.ID = state.ID
+	plan.Status = state.Status
+
+	// The email forces replacement, so resend_trigger is the only attribute
+	// that can change in place.
+	if !plan.ResendTrigger.Equal(state.ResendTrigger) {
+		if state.Status.ValueString() == sdp.Invite_INVITE_STATUS_ACCEPTED.String() {
+			resp.Diagnostics.AddWarning("Invite not resent",
+				fmt.Sprintf("%s has already accepted the invite, so it was not resent.", state.ID.ValueString()))
+		} else {
+			span.SetAttributes(attribute.Bool("ovm.invite.resent", true))
+			_, err := r.invites.ResendInvite(ctx, connect.NewRequest(&sdp.ResendInviteRequest{
+				Email: state.ID.ValueString(),
+			}))
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to resend invite", err.Error())
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "ResendInvite failed")
+				return
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *teamInviteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamInvite Delete")
+	defer span.End()
+
+	var state teamInviteResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("ovm.invite.email", state.ID.ValueString()))
+
+	_, err := r.invites.RevokeInvite(ctx, connect.NewRequest(&sdp.RevokeInviteRequest{
+		Email: state.ID.ValueString(),
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			span.SetAttributes(attribute.Bool("ovm.invite.alreadyGone", true))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to revoke invite", err.Error())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "RevokeInvite failed")
+	}
+}
+
+func (r *teamInviteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, span := tracing.Tracer().Start(ctx, "TeamInvite Import")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("ovm.invite.email", req.ID))
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("email"), req.ID)...)
+}
+
+// findInvite returns the invite sent to email, or nil if there is none.
+// Emails are compared case-insensitively, as the API does.
+func findInvite(ctx context.Context, invites sdpconnect.InviteServiceClient, email string) (*sdp.Invite, error) {
+	listResp, err := invites.ListInvites(ctx, connect.NewRequest(&sdp.ListInvitesRequest{}))
+	if err != nil {
+		return nil, err
+	}
+	for _, invite := range listResp.Msg.GetInvites() {
+		if strings.EqualFold(invite.GetEmail(), email) {
+			return invite, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+)
+
+// --- mock InviteService handler ---
+
+type mockInviteHandler struct {
+	sdpconnect.UnimplementedInviteServiceHandler
+	mu      sync.Mutex
+	invites map[string]*sdp.Invite
+	resent  map[string]int
+}
+
+func newMockInviteHandler() *mockInviteHandler {
+	return &mockInviteHandler{
+		invites: make(map[string]*sdp.Invite),
+		resent:  make(map[string]int),
+	}
+}
+
+func (m *mockInviteHandler) CreateInvite(_ context.Context, req *connect.Request[sdp.CreateInviteRequest]) (*connect.Response[sdp.CreateInviteResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, email := range req.Msg.GetEmails() {
+		m.invites[strings.ToLower(email)] = &sdp.Invite{Email: email, Status: sdp.Invite_INVITE_STATUS_INVITED}
+	}
+	return connect.NewResponse(&sdp.CreateInviteResponse{}), nil
+}
+
+func (m *mockInviteHandler) ListInvites(_ context.Context, _ *connect.Request[sdp.ListInvitesRequest]) (*connect.Response[sdp.ListInvitesResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invites := make([]*sdp.Invite, 0, len(m.invites))
+	for _, invite := range m.invites {
+		invites = append(invites, invite)
+	}
+	return connect.NewResponse(&sdp.ListInvitesResponse{Invites: invites}), nil
+}
+
+func (m *mockInviteHandler) ResendInvite(_ context.Context, req *connect.Request[sdp.ResendInviteRequest]) (*connect.Response[sdp.ResendInviteResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	email := strings.ToLower(req.Msg.GetEmail())
+	if _, ok := m.invites[email]; !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	m.resent[email]++
+	return connect.NewResponse(&sdp.ResendInviteResponse{}), nil
+}
+
+func (m *mockInviteHandler) RevokeInvite(_ context.Context, req *connect.Request[sdp.RevokeInviteRequest]) (*connect.Response[sdp.RevokeInviteResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	email := strings.ToLower(req.Msg.GetEmail())
+	if _, ok := m.invites[email]; !ok {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	}
+	delete(m.invites, email)
+	return connect.NewResponse(&sdp.RevokeInviteResponse{}), nil
+}
+
+func (m *mockMgmtHandler) ListTeamMembers(_ context.Context, _ *connect.Request[sdp.ListTeamMembersRequest]) (*connect.Response[sdp.ListTeamMembersResponse], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return connect.NewResponse(&sdp.ListTeamMembersResponse{Members: m.members}), nil
+}
+
+// --- tests ---
+
+func testAccTeamInviteConfig(resend string) string {
+	return `resource "overmind_team_invite" "alice" {
+  email          = "Alice@example.com"
+  resend_trigger = "` + resend + `"
+}`
+}
+
+func TestTeamInviteResource_CRUD(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		CheckDestroy: func(_ *terraform.State) error {
+			if len(mocks.invites.invites) != 0 {
+				return fmt.Errorf("expected all invites to be revoked, %d remain", len(mocks.invites.invites))
+			}
+			return nil
+		},
+		Steps: []tfresource.TestStep{
+			{
+				Config: testAccTeamInviteConfig("1"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("overmind_team_invite.alice", "id", "Alice@example.com"),
+					tfresource.TestCheckResourceAttr("overmind_team_invite.alice", "status", "INVITE_STATUS_INVITED"),
+				),
+			},
+			{
+				Config: testAccTeamInviteConfig("2"),
+				Check: func(_ *terraform.State) error {
+					if n := mocks.invites.resent["alice@example.com"]; n != 1 {
+						return fmt.Errorf("expected the invite to be resent once, got %d", n)
+					}
+					return nil
+				},
+			},
+			{
+				// Accepted outside of Terraform.
+				PreConfig: func() {
+					mocks.invites.mu.Lock()
+					mocks.invites.invites["alice@example.com"].Status = sdp.Invite_INVITE_STATUS_ACCEPTED
+					mocks.invites.mu.Unlock()
+				},
+				Config: testAccTeamInviteConfig("2"),
+				Check:  tfresource.TestCheckResourceAttr("overmind_team_invite.alice", "status", "INVITE_STATUS_ACCEPTED"),
+			},
+			{
+				ResourceName:            "overmind_team_invite.alice",
+				ImportState:             true,
+				ImportStateId:           "Alice@example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"resend_trigger"},
+			},
+		},
+	})
+}
+
+func TestTeamMembersDataSource_Read(t *testing.T) {
+	serverURL, mocks := startMockServer(t)
+	bob, alice := uuid.New(), uuid.New()
+	mocks.mgmt.members = []*sdp.TeamMember{
+		{UUID: bob[:], Name: "Bob"},
+		{UUID: alice[:], Name: "Alice", PictureUrl: "https://example.com/alice.png"},
+	}
+
+	tfresource.UnitTest(t, tfresource.TestCase{
+		ProtoV6ProviderFactories: unitTestProviderFactories(serverURL),
+		Steps: []tfresource.TestStep{
+			{
+				Config: `data "overmind_team_members" "all" {}`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("data.overmind_team_members.all", "members.#", "2"),
+					tfresource.TestCheckResourceAttr("data.overmind_team_members.all", "names.0", "Alice"),
+					tfresource.TestCheckResourceAttr("data.overmind_team_members.all", "ids.0", alice.String()),
+					tfresource.TestCheckResourceAttr("data.overmind_team_members.all", "members.0.picture_url", "https://example.com/alice.png"),
+					tfresource.TestCheckNoResourceAttr("data.overmind_team_members.all", "members.1.picture_url"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"google.golang.org/protobuf/proto"
+)
+
+// signalConfigYAMLType is a string type for signal config YAML documents.
+// Values that parse to the same configuration are semantically equal, so
+// reformatting the YAML or reordering its keys does not cause a diff.
+type signalConfigYAMLType struct {
+	basetypes.StringType
+}
+
+var _ basetypes.StringTypable = signalConfigYAMLType{}
+
+func (t signalConfigYAMLType) String() string {
+	return "signalConfigYAMLType"
+}
+
+func (t signalConfigYAMLType) Equal(o attr.Type) bool {
+	_, ok := o.(signalConfigYAMLType)
+	return ok
+}
+
+func (t signalConfigYAMLType) ValueType(_ context.Context) attr.Value {
+	return signalConfigYAMLValue{}
+}
+
+func (t signalConfigYAMLType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return signalConfigYAMLValue{StringValue: in}, nil
+}
+
+func (t signalConfigYAMLType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type %T", attrValue)
+	}
+	return signalConfigYAMLValue{StringValue: stringValue}, nil
+}
+
+type signalConfigYAMLValue struct {
+	basetypes.StringValue
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = signalConfigYAMLValue{}
+
+func signalConfigYAMLNull() signalConfigYAMLValue {
+	return signalConfigYAMLValue{StringValue: basetypes.NewStringNull()}
+}
+
+func signalConfigYAMLUnknown() signalConfigYAMLValue {
+	return signalConfigYAMLValue{StringValue: basetypes.NewStringUnknown()}
+}
+
+func newSignalConfigYAMLValue(s string) signalConfigYAMLValue {
+	return signalConfigYAMLValue{StringValue: basetypes.NewStringValue(s)}
+}
+
+func (v signalConfigYAMLValue) Type(_ context.Context) attr.Type {
+	return signalConfigYAMLType{}
+}
+
+func (v signalConfigYAMLValue) Equal(o attr.Value) bool {
+	other, ok := o.(signalConfigYAMLValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether both documents parse to the same
+// signal configuration. Documents that do not parse are only equal to
+// themselves.
+func (v signalConfigYAMLValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	newValue, ok := newValuable.(signalConfigYAMLValue)
+	if !ok {
+		return false, nil
+	}
+
+	oldConfig, err := sdp.YamlStringToSignalConfig(v.ValueString())
+	if err != nil {
+		return false, nil
+	}
+	newConfig, err := sdp.YamlStringToSignalConfig(newValue.ValueString())
+	if err != nil {
+		return false, nil
+	}
+
+	return proto.Equal(oldConfig.RoutineChangesConfig, newConfig.RoutineChangesConfig) &&
+		proto.Equal(oldConfig.GithubOrganisationProfile, newConfig.GithubOrganisationProfile), nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+	"github.com/overmindtech/terraform-provider-overmind/go/sdp-go/sdpconnect"
+	"github.com/overmindtech/terraform-provider-overmind/go/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultSourceHealthTimeout is used when no `timeouts {}` value is configured.
+const defaultSourceHealthTimeout = 10 * time.Minute
+
+// sourceHealthPollInterval is how often GetSourceStatus is polled while
+// waiting for a source to become healthy. Tests shorten it.
+var sourceHealthPollInterval = 5 * time.Second //nolint:gochecknoglobals // overridden in tests
+
+// errSourceUnhealthy is returned when the source reports an error while we
+// are waiting for it to become healthy.
+var errSourceUnhealthy = errors.New("source is unhealthy")
+
+// waitForSourceHealthy polls GetSourceStatus until the source reports
+// STATUS_HEALTHY, reports an error, or the timeout expires. A freshly created
+// source is allowed to be missing or disconnected for a while before its
+// first heartbeat arrives. If lastHeartbeat is set, it is the heartbeat the
+// source reported before an update, and statuses from that heartbeat or
+// earlier were reported for the previous config and are ignored.
+func waitForSourceHealthy(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceUUID []byte, lastHeartbeat time.Time, timeout time.Duration) (*sdp.SourceHealth, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "WaitForSourceHealthy")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(sourceHealthPollInterval)
+	defer ticker.Stop()
+
+	var last *sdp.SourceHealth
+	var stale bool
+	for {
+		statusResp, err := mgmt.GetSourceStatus(ctx, connect.NewRequest(&sdp.GetSourceStatusRequest{
+			SourceUuid: sourceUUID,
+		}))
+		switch {
+		case err == nil:
+			last = statusResp.Msg.GetSource()
+			span.SetAttributes(attribute.String("ovm.source.status", last.GetStatus().String()))
+
+			stale = !lastHeartbeat.IsZero() && !last.GetLastHeartbeat().AsTime().After(lastHeartbeat)
+			if stale {
+				// The source has not reported in since the update, keep waiting.
+				break
+			}
+			if last.GetStatus() == sdp.SourceStatus_STATUS_HEALTHY {
+				return last, nil
+			}
+			if last.GetStatus() == sdp.SourceStatus_STATUS_UNHEALTHY && last.GetError() != "" {
+				err = fmt.Errorf("%w: %s", errSourceUnhealthy, last.GetError())
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "source unhealthy")
+				return last, err
+			}
+		case connect.CodeOf(err) == connect.CodeNotFound:
+			// The source has not registered yet, keep waiting.
+		case ctx.Err() == nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "GetSourceStatus failed")
+			return last, fmt.Errorf("getting source status: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			err := fmt.Errorf("timed out after %s waiting for source to become healthy (last status: %s)",
+				timeout, last.GetStatus())
+			if stale {
+				err = fmt.Errorf("timed out after %s waiting for source to report in after the update (last status: %s)",
+					timeout, last.GetStatus())
+			} else if last.GetError() != "" {
+				err = fmt.Errorf("%w: %s", err, last.GetError())
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "timeout")
+			return last, err
+		case <-ticker.C:
+		}
+	}
+}
+
+// sourceHealthAttrTypes describes the `health` object exposed on source
+// resources.
+var sourceHealthAttrTypes = map[string]attr.Type{ //nolint:gochecknoglobals // schema type definition
+	"status":           types.StringType,
+	"error":            types.StringType,
+	"version":          types.StringType,
+	"managed":          types.BoolType,
+	"last_heartbeat":   types.StringType,
+	"next_heartbeat":   types.StringType,
+	"available_types":  types.ListType{ElemType: types.StringType},
+	"available_scopes": types.ListType{ElemType: types.StringType},
+}
+
+func sourceHealthSchemaAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Health of the source as last reported to Overmind. Null until the source first reports in.",
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"status": schema.StringAttribute{
+				Description: "Source status, e.g. STATUS_HEALTHY, STATUS_UNHEALTHY or STATUS_PROGRESSING.",
+				Computed:    true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported by the source, if it is unhealthy.",
+				Computed:    true,
+			},
+			"version": schema.StringAttribute{
+				Description: "Version of the running source.",
+				Computed:    true,
+			},
+			"managed": schema.BoolAttribute{
+				Description: "Whether the source is run by Overmind rather than self-hosted.",
+				Computed:    true,
+			},
+			"last_heartbeat": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the last heartbeat received from the source.",
+				Computed:    true,
+			},
+			"next_heartbeat": schema.StringAttribute{
+				Description: "RFC 3339 timestamp of the next heartbeat expected from the source.",
+				Computed:    true,
+			},
+			"available_types": schema.ListAttribute{
+				Description: "Item types the source can discover.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"available_scopes": schema.ListAttribute{
+				Description: "Scopes the source can discover, e.g. AWS account/region pairs.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// sourceHealthObject converts a SourceHealth into the `health` object. A nil
+// SourceHealth converts to null.
+func sourceHealthObject(ctx context.Context, health *sdp.SourceHealth) (types.Object, diag.Diagnostics) {
+	if health == nil {
+		return types.ObjectNull(sourceHealthAttrTypes), nil
+	}
+
+	var diags diag.Diagnostics
+	availableTypes, d := types.ListValueFrom(ctx, types.StringType, nonNilStrings(health.GetAvailableTypes()))
+	diags.Append(d...)
+	availableScopes, d := types.ListValueFrom(ctx, types.StringType, nonNilStrings(health.GetAvailableScopes()))
+	diags.Append(d...)
+
+	obj, d := types.ObjectValue(sourceHealthAttrTypes, map[string]attr.Value{
+		"status":           types.StringValue(health.GetStatus().String()),
+		"error":            stringOrNull(health.GetError()),
+		"version":          stringOrNull(health.GetVersion()),
+		"managed":          types.BoolValue(health.GetManaged() == sdp.SourceManaged_MANAGED),
+		"last_heartbeat":   timestampString(health.GetLastHeartbeat()),
+		"next_heartbeat":   timestampString(health.GetNextHeartbeat()),
+		"available_types":  availableTypes,
+		"available_scopes": availableScopes,
+	})
+	diags.Append(d...)
+	return obj, diags
+}
+
+// lastSourceHeartbeat returns when the source last reported in, or the zero
+// time if it never has. The time comes from Overmind, so comparing it with
+// later heartbeats does not depend on the local clock.
+func lastSourceHeartbeat(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceUUID []byte) (time.Time, error) {
+	statusResp, err := mgmt.GetSourceStatus(ctx, connect.NewRequest(&sdp.GetSourceStatusRequest{
+		SourceUuid: sourceUUID,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if heartbeat := statusResp.Msg.GetSource().GetLastHeartbeat(); heartbeat != nil {
+		return heartbeat.AsTime(), nil
+	}
+	return time.Time{}, nil
+}
+
+// readSourceHealth fetches the current health of a source. Failing to read
+// the health must not break refresh of the source itself, so errors are
+// reported as warnings and result in a null object.
+func readSourceHealth(ctx context.Context, mgmt sdpconnect.ManagementServiceClient, sourceUUID []byte) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	statusResp, err := mgmt.GetSourceStatus(ctx, connect.NewRequest(&sdp.GetSourceStatusRequest{
+		SourceUuid: sourceUUID,
+	}))
+	if err != nil {
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			diags.AddWarning("Failed to read source health", err.Error())
+		}
+		return types.ObjectNull(sourceHealthAttrTypes), diags
+	}
+
+	obj, d := sourceHealthObject(ctx, statusResp.Msg.GetSource())
+	diags.Append(d...)
+	return obj, diags
+}
+
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+func timestampString(ts *timestamppb.Timestamp) types.String {
+	if ts == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(ts.AsTime().UTC().Format(time.RFC3339))
+}
+
+func nonNilStrings(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
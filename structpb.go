@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// structFromDynamic converts a dynamic Terraform value holding an object or
+// map into a protobuf Struct. A null value converts to a nil Struct so that
+// optional config blocks are simply omitted from the request.
+func structFromDynamic(ctx context.Context, v types.Dynamic) (*structpb.Struct, error) {
+	if v.IsNull() || v.IsUnderlyingValueNull() {
+		return nil, nil //nolint:nilnil // a nil struct is the "not set" value
+	}
+	if v.IsUnknown() || v.IsUnderlyingValueUnknown() {
+		return nil, fmt.Errorf("value is not yet known")
+	}
+
+	native, err := nativeFromAttr(v.UnderlyingValue())
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := native.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %s", v.UnderlyingValue().Type(ctx))
+	}
+	return structpb.NewStruct(fields)
+}
+
+// dynamicFromStruct converts a protobuf Struct into a dynamic Terraform
+// value. Nested structs become objects and lists become tuples, which matches
+// the types Terraform infers for HCL object and tuple literals and keeps the
+// value read back from the API comparable with the configured one. A nil
+// Struct converts to null.
+func dynamicFromStruct(ctx context.Context, s *structpb.Struct) (types.Dynamic, diag.Diagnostics) {
+	if s == nil {
+		return types.DynamicNull(), nil
+	}
+	v, diags := attrFromStructValue(ctx, structpb.NewStructValue(s))
+	if diags.HasError() {
+		return types.DynamicNull(), diags
+	}
+	return types.DynamicValue(v), diags
+}
+
+// nativeFromAttr converts a framework value into the plain Go types accepted
+// by structpb.NewValue.
+func nativeFromAttr(v attr.Value) (any, error) {
+	if v == nil || v.IsNull() {
+		return nil, nil
+	}
+	if v.IsUnknown() {
+		return nil, fmt.Errorf("value is not yet known")
+	}
+
+	switch val := v.(type) {
+	case basetypes.DynamicValue:
+		return nativeFromAttr(val.UnderlyingValue())
+	case basetypes.StringValue:
+		return val.ValueString(), nil
+	case basetypes.BoolValue:
+		return val.ValueBool(), nil
+	case basetypes.NumberValue:
+		f, _ := val.ValueBigFloat().Float64()
+		return f, nil
+	case basetypes.Int64Value:
+		return float64(val.ValueInt64()), nil
+	case basetypes.Int32Value:
+		return float64(val.ValueInt32()), nil
+	case basetypes.Float64Value:
+		return val.ValueFloat64(), nil
+	case basetypes.Float32Value:
+		return float64(val.ValueFloat32()), nil
+	case basetypes.ListValue:
+		return nativeFromElements(val.Elements())
+	case basetypes.SetValue:
+		return nativeFromElements(val.Elements())
+	case basetypes.TupleValue:
+		return nativeFromElements(val.Elements())
+	case basetypes.MapValue:
+		return nativeFromAttributes(val.Elements())
+	case basetypes.ObjectValue:
+		return nativeFromAttributes(val.Attributes())
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+func nativeFromElements(elems []attr.Value) ([]any, error) {
+	out := make([]any, len(elems))
+	for i, e := range elems {
+		n, err := nativeFromAttr(e)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func nativeFromAttributes(attrs map[string]attr.Value) (map[string]any, error) {
+	out := make(map[string]any, len(attrs))
+	for k, e := range attrs {
+		n, err := nativeFromAttr(e)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k, err)
+		}
+		out[k] = n
+	}
+	return out, nil
+}
+
+// attrFromStructValue converts a single protobuf Value into a framework
+// value. Nulls become dynamic nulls because their type cannot be inferred.
+func attrFromStructValue(ctx context.Context, v *structpb.Value) (attr.Value, diag.Diagnostics) {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		return types.StringValue(kind.StringValue), nil
+	case *structpb.Value_BoolValue:
+		return types.BoolValue(kind.BoolValue), nil
+	case *structpb.Value_NumberValue:
+		return types.NumberValue(big.NewFloat(kind.NumberValue)), nil
+	case *structpb.Value_ListValue:
+		var diags diag.Diagnostics
+		values := kind.ListValue.GetValues()
+		elemTypes := make([]attr.Type, len(values))
+		elems := make([]attr.Value, len(values))
+		for i, item := range values {
+			e, d := attrFromStructValue(ctx, item)
+			diags.Append(d...)
+			elems[i] = e
+			elemTypes[i] = e.Type(ctx)
+		}
+		if diags.HasError() {
+			return types.DynamicNull(), diags
+		}
+		tuple, d := types.TupleValue(elemTypes, elems)
+		diags.Append(d...)
+		return tuple, diags
+	case *structpb.Value_StructValue:
+		var diags diag.Diagnostics
+		fields := kind.StructValue.GetFields()
+		attrTypes := make(map[string]attr.Type, len(fields))
+		attrs := make(map[string]attr.Value, len(fields))
+		for k, field := range fields {
+			a, d := attrFromStructValue(ctx, field)
+			diags.Append(d...)
+			attrs[k] = a
+			attrTypes[k] = a.Type(ctx)
+		}
+		if diags.HasError() {
+			return types.DynamicNull(), diags
+		}
+		obj, d := types.ObjectValue(attrTypes, attrs)
+		diags.Append(d...)
+		return obj, diags
+	default:
+		return types.DynamicNull(), nil
+	}
+}
+
+// mergeSourceConfig overlays the managed config keys onto the config that is
+// currently stored for a source. Owned keys that are no longer managed (e.g.
+// after switching AWS access strategy) are dropped, while keys the resource
+// does not own are preserved and returned in sorted order so that the caller
+// can tell the user about them.
+func mergeSourceConfig(current *structpb.Struct, owned []string, managed map[string]any) (*structpb.Struct, []string, error) {
+	merged, err := structpb.NewStruct(managed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unmanaged []string
+	for k, v := range current.GetFields() {
+		if slices.Contains(owned, k) {
+			continue
+		}
+		if _, ok := merged.Fields[k]; ok {
+			continue
+		}
+		merged.Fields[k] = v
+		unmanaged = append(unmanaged, k)
+	}
+	sort.Strings(unmanaged)
+
+	return merged, unmanaged, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestStructDynamicRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	original, err := structpb.NewStruct(map[string]any{
+		"string": "value",
+		"number": 42.5,
+		"bool":   true,
+		"list":   []any{"a", 1.0, false},
+		"nested": map[string]any{
+			"inner": []any{map[string]any{"deep": "yes"}},
+			"empty": map[string]any{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dyn, diags := dynamicFromStruct(ctx, original)
+	if diags.HasError() {
+		t.Fatalf("dynamicFromStruct: %v", diags)
+	}
+
+	roundTripped, err := structFromDynamic(ctx, dyn)
+	if err != nil {
+		t.Fatalf("structFromDynamic: %v", err)
+	}
+
+	if !proto.Equal(original, roundTripped) {
+		t.Errorf("round trip mismatch:\nwant %v\ngot  %v", original, roundTripped)
+	}
+
+	// Converting the same struct twice must yield equal values, otherwise
+	// Read would report drift on every refresh.
+	again, _ := dynamicFromStruct(ctx, roundTripped)
+	if !dyn.Equal(again) {
+		t.Errorf("expected stable conversion, got %v and %v", dyn, again)
+	}
+}
+
+func TestStructFromDynamic_Null(t *testing.T) {
+	s, err := structFromDynamic(context.Background(), types.DynamicNull())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != nil {
+		t.Errorf("expected nil struct, got %v", s)
+	}
+}
+
+func TestStructFromDynamic_NotAnObject(t *testing.T) {
+	_, err := structFromDynamic(context.Background(), types.DynamicValue(types.StringValue("nope")))
+	if err == nil {
+		t.Error("expected an error for a non-object value")
+	}
+}
+
+func TestMergeSourceConfig(t *testing.T) {
+	current, err := structpb.NewStruct(map[string]any{
+		"aws-access-strategy": "external-id",
+		"aws-target-role-arn": "arn:aws:iam::123456789012:role/old",
+		"aws-regions":         []any{"us-east-1"},
+		"custom-setting":      "from-the-ui",
+		"another-setting":     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owned := []string{"aws-access-strategy", "aws-target-role-arn", "aws-regions", "aws-profile"}
+	merged, unmanaged, err := mergeSourceConfig(current, owned, map[string]any{
+		"aws-access-strategy": "sso-profile",
+		"aws-profile":         "readonly",
+		"aws-regions":         []any{"eu-west-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := structpb.NewStruct(map[string]any{
+		"aws-access-strategy": "sso-profile",
+		"aws-profile":         "readonly",
+		"aws-regions":         []any{"eu-west-1"},
+		"custom-setting":      "from-the-ui",
+		"another-setting":     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(want, merged) {
+		t.Errorf("merged config mismatch:\nwant %v\ngot  %v", want, merged)
+	}
+
+	if !slices.Equal(unmanaged, []string{"another-setting", "custom-setting"}) {
+		t.Errorf("unexpected unmanaged keys %v", unmanaged)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+)
+
+// Items built from a plan live in their own scope and are named by their
+// Terraform address, so Overmind can map them to real infrastructure.
+const (
+	planItemScope           = "terraform_plan"
+	planItemUniqueAttribute = "terraform_name"
+	sensitiveValueMask      = "(sensitive value)"
+)
+
+// readPlanFile parses the JSON representation of a Terraform plan, as written
+// by `terraform show -json`.
+func readPlanFile(name string) (*tfjson.Plan, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan file: %w", err)
+	}
+	var plan tfjson.Plan
+	if err := json.Unmarshal(b, &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan file %s: %w", name, err)
+	}
+	return &plan, nil
+}
+
+// mappedItemDiffsFromPlan converts the managed resource changes of a plan into
+// changing items. The provider does not know how to query for Terraform
+// resource types, so the items carry no mapping query: resources that will be
+// created are marked as pending creation and everything else as unsupported,
+// leaving it to Overmind to find the matching infrastructure. Resources with
+// no changes and data sources are left out.
+func mappedItemDiffsFromPlan(plan *tfjson.Plan) ([]*sdp.MappedItemDiff, error) {
+	var diffs []*sdp.MappedItemDiff
+	for _, rc := range plan.ResourceChanges {
+		if rc.Mode != tfjson.ManagedResourceMode || rc.Change == nil {
+			continue
+		}
+		status := itemDiffStatus(rc.Change.Actions)
+		if status == sdp.ItemDiffStatus_ITEM_DIFF_STATUS_UNCHANGED {
+			continue
+		}
+
+		diff := &sdp.ItemDiff{Status: status}
+		var err error
+		if rc.Change.Before != nil {
+			diff.Before, err = planItem(rc, maskSensitive(rc.Change.Before, rc.Change.BeforeSensitive))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if rc.Change.After != nil {
+			diff.After, err = planItem(rc, maskSensitive(rc.Change.After, rc.Change.AfterSensitive))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		mappingStatus := sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_UNSUPPORTED
+		if status == sdp.ItemDiffStatus_ITEM_DIFF_STATUS_CREATED {
+			mappingStatus = sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_PENDING_CREATION
+		}
+		diffs = append(diffs, &sdp.MappedItemDiff{
+			Item:          diff,
+			MappingStatus: &mappingStatus,
+		})
+	}
+	return diffs, nil
+}
+
+func itemDiffStatus(actions tfjson.Actions) sdp.ItemDiffStatus {
+	switch {
+	case actions.NoOp(), actions.Read(), actions.Forget():
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_UNCHANGED
+	case actions.Create():
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_CREATED
+	case actions.Update():
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_UPDATED
+	case actions.Replace():
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_REPLACED
+	case actions.Delete():
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_DELETED
+	default:
+		return sdp.ItemDiffStatus_ITEM_DIFF_STATUS_UNSPECIFIED
+	}
+}
+
+func planItem(rc *tfjson.ResourceChange, values any) (*sdp.Item, error) {
+	attrs, err := sdp.ToAttributesViaJson(values)
+	if err != nil {
+		return nil, fmt.Errorf("converting attributes of %s: %w", rc.Address, err)
+	}
+	if err := attrs.Set(planItemUniqueAttribute, rc.Address); err != nil {
+		return nil, fmt.Errorf("naming %s: %w", rc.Address, err)
+	}
+	if err := attrs.Set("terraform_address", rc.Address); err != nil {
+		return nil, fmt.Errorf("naming %s: %w", rc.Address, err)
+	}
+	return &sdp.Item{
+		Type:            rc.Type,
+		UniqueAttribute: planItemUniqueAttribute,
+		Scope:           planItemScope,
+		Attributes:      attrs,
+	}, nil
+}
+
+// maskSensitive replaces the values that the plan marks as sensitive, so they
+// are never sent to Overmind. sensitive mirrors the shape of value, with true
+// wherever the value is sensitive.
+func maskSensitive(value, sensitive any) any {
+	switch s := sensitive.(type) {
+	case bool:
+		if s {
+			return sensitiveValueMask
+		}
+		return value
+	case map[string]any:
+		v, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		masked := make(map[string]any, len(v))
+		for k, item := range v {
+			masked[k] = maskSensitive(item, s[k])
+		}
+		return masked
+	case []any:
+		v, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		masked := make([]any, len(v))
+		for i, item := range v {
+			if i < len(s) {
+				masked[i] = maskSensitive(item, s[i])
+			} else {
+				masked[i] = item
+			}
+		}
+		return masked
+	default:
+		return value
+	}
+}
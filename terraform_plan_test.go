@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	sdp "github.com/overmindtech/terraform-provider-overmind/go/sdp-go"
+)
+
+// testPlanJSON is a trimmed `terraform show -json` plan with one resource of
+// each kind of change, a data source read and an unchanged resource.
+const testPlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.0",
+  "resource_changes": [
+    {
+      "address": "aws_db_instance.orders",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "orders",
+      "change": {
+        "actions": ["update"],
+        "before": {"instance_class": "db.t3.medium", "password": "hunter2", "tags": {"team": "payments"}},
+        "after": {"instance_class": "db.t3.large", "password": "hunter3", "tags": {"team": "payments"}},
+        "before_sensitive": {"password": true, "tags": {}},
+        "after_sensitive": {"password": true, "tags": {}}
+      }
+    },
+    {
+      "address": "aws_security_group.orders",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "orders",
+      "change": {"actions": ["create"], "before": null, "after": {"name": "orders"}}
+    },
+    {
+      "address": "aws_instance.legacy[0]",
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "legacy",
+      "index": 0,
+      "change": {"actions": ["delete"], "before": {"id": "i-0123456789"}, "after": null}
+    },
+    {
+      "address": "aws_lb.front",
+      "mode": "managed",
+      "type": "aws_lb",
+      "name": "front",
+      "change": {"actions": ["create", "delete"], "before": {"name": "front"}, "after": {"name": "front"}}
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["no-op"], "before": {"bucket": "logs"}, "after": {"bucket": "logs"}}
+    },
+    {
+      "address": "data.aws_caller_identity.current",
+      "mode": "data",
+      "type": "aws_caller_identity",
+      "name": "current",
+      "change": {"actions": ["read"], "before": null, "after": {}}
+    }
+  ]
+}`
+
+func TestMappedItemDiffsFromPlan(t *testing.T) {
+	var plan tfjson.Plan
+	if err := json.Unmarshal([]byte(testPlanJSON), &plan); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := mappedItemDiffsFromPlan(&plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		gun           string
+		status        sdp.ItemDiffStatus
+		mappingStatus sdp.MappedItemMappingStatus
+	}{
+		{"terraform_plan.aws_db_instance.aws_db_instance.orders", sdp.ItemDiffStatus_ITEM_DIFF_STATUS_UPDATED, sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_UNSUPPORTED},
+		{"terraform_plan.aws_security_group.aws_security_group.orders", sdp.ItemDiffStatus_ITEM_DIFF_STATUS_CREATED, sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_PENDING_CREATION},
+		{"terraform_plan.aws_instance.aws_instance.legacy[0]", sdp.ItemDiffStatus_ITEM_DIFF_STATUS_DELETED, sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_UNSUPPORTED},
+		{"terraform_plan.aws_lb.aws_lb.front", sdp.ItemDiffStatus_ITEM_DIFF_STATUS_REPLACED, sdp.MappedItemMappingStatus_MAPPED_ITEM_MAPPING_STATUS_UNSUPPORTED},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("expected %d changing items, got %d", len(want), len(diffs))
+	}
+	for i, w := range want {
+		diff := diffs[i]
+		if got := diff.GetItem().GloballyUniqueName(); got != w.gun {
+			t.Errorf("item %d: expected %s, got %s", i, w.gun, got)
+		}
+		if got := diff.GetItem().GetStatus(); got != w.status {
+			t.Errorf("%s: expected status %s, got %s", w.gun, w.status, got)
+		}
+		if got := diff.GetMappingStatus(); got != w.mappingStatus {
+			t.Errorf("%s: expected mapping status %s, got %s", w.gun, w.mappingStatus, got)
+		}
+		if diff.MappingQuery != nil {
+			t.Errorf("%s: expected no mapping query", w.gun)
+		}
+	}
+
+	update := diffs[0].GetItem()
+	for name, item := range map[string]*sdp.Item{"before": update.GetBefore(), "after": update.GetAfter()} {
+		password, err := item.GetAttributes().Get("password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if password != sensitiveValueMask {
+			t.Errorf("%s: expected the password to be masked, got %v", name, password)
+		}
+	}
+	class, err := update.GetAfter().GetAttributes().Get("instance_class")
+	if err != nil || class != "db.t3.large" {
+		t.Errorf("expected the new instance class, got %v (%v)", class, err)
+	}
+	if diffs[1].GetItem().GetBefore() != nil {
+		t.Error("expected a created item to have no before state")
+	}
+}
+
+func TestMaskSensitive(t *testing.T) {
+	value := map[string]any{
+		"name":  "orders",
+		"users": []any{map[string]any{"name": "admin", "password": "hunter2"}, map[string]any{"name": "ro"}},
+		"token": "abc",
+	}
+	sensitive := map[string]any{
+		"users": []any{map[string]any{"password": true}},
+		"token": true,
+	}
+
+	masked, ok := maskSensitive(value, sensitive).(map[string]any)
+	if !ok {
+		t.Fatalf("expected a map, got %T", masked)
+	}
+	if masked["token"] != sensitiveValueMask || masked["name"] != "orders" {
+		t.Errorf("unexpected top-level values: %v", masked)
+	}
+	users := masked["users"].([]any)
+	if users[0].(map[string]any)["password"] != sensitiveValueMask || users[0].(map[string]any)["name"] != "admin" {
+		t.Errorf("unexpected first user: %v", users[0])
+	}
+	if users[1].(map[string]any)["name"] != "ro" {
+		t.Errorf("unexpected second user: %v", users[1])
+	}
+	if value["token"] != "abc" {
+		t.Error("maskSensitive modified its input")
+	}
+}
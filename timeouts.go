@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// timeoutsModel backs the `timeouts {}` block. It has the same shape as the
+// block from terraform-plugin-framework-timeouts so configurations look the
+// same as for any other provider.
+type timeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+}
+
+func timeoutsBlock() schema.Block {
+	return schema.SingleNestedBlock{
+		Description: "Timeouts for create and update operations, as Go duration strings such as \"30s\" or \"10m\".",
+		Attributes: map[string]schema.Attribute{
+			"create": schema.StringAttribute{
+				Description: "How long to wait for a newly created resource to become ready.",
+				Optional:    true,
+			},
+			"update": schema.StringAttribute{
+				Description: "How long to wait for an updated resource to become ready.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// validate checks that all configured timeouts parse as durations.
+func (t *timeoutsModel) validate() diag.Diagnostics {
+	var diags diag.Diagnostics
+	if t == nil {
+		return diags
+	}
+	for name, v := range map[string]types.String{"create": t.Create, "update": t.Update} {
+		if v.IsNull() || v.IsUnknown() {
+			continue
+		}
+		if _, err := time.ParseDuration(v.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("timeouts").AtName(name), "Invalid timeout",
+				fmt.Sprintf("timeouts.%s must be a duration such as \"10m\": %s", name, err))
+		}
+	}
+	return diags
+}
+
+// CreateTimeout returns the configured create timeout, or def if unset.
+func (t *timeoutsModel) CreateTimeout(def time.Duration) (time.Duration, diag.Diagnostics) {
+	if t == nil {
+		return def, nil
+	}
+	return parseTimeout("create", t.Create, def)
+}
+
+// UpdateTimeout returns the configured update timeout, or def if unset.
+func (t *timeoutsModel) UpdateTimeout(def time.Duration) (time.Duration, diag.Diagnostics) {
+	if t == nil {
+		return def, nil
+	}
+	return parseTimeout("update", t.Update, def)
+}
+
+// readTimeoutsModel backs the `timeouts {}` block of data sources, which
+// only have a read timeout.
+type readTimeoutsModel struct {
+	Read types.String `tfsdk:"read"`
+}
+
+func readTimeoutsBlock() dsschema.Block {
+	return dsschema.SingleNestedBlock{
+		Description: "Timeouts for read operations, as Go duration strings such as \"30s\" or \"10m\".",
+		Attributes: map[string]dsschema.Attribute{
+			"read": dsschema.StringAttribute{
+				Description: "How long to wait for the data to become available.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// validate checks that the read timeout, if configured, parses as a duration.
+func (t *readTimeoutsModel) validate() diag.Diagnostics {
+	_, diags := t.ReadTimeout(0)
+	return diags
+}
+
+// ReadTimeout returns the configured read timeout, or def if unset.
+func (t *readTimeoutsModel) ReadTimeout(def time.Duration) (time.Duration, diag.Diagnostics) {
+	if t == nil {
+		return def, nil
+	}
+	return parseTimeout("read", t.Read, def)
+}
+
+// invokeTimeoutsModel backs the `timeouts {}` block of actions, which only
+// have an invoke timeout.
+type invokeTimeoutsModel struct {
+	Invoke types.String `tfsdk:"invoke"`
+}
+
+func invokeTimeoutsBlock() actionschema.Block {
+	return actionschema.SingleNestedBlock{
+		Description: "Timeouts for invoking the action, as Go duration strings such as \"30s\" or \"10m\".",
+		Attributes: map[string]actionschema.Attribute{
+			"invoke": actionschema.StringAttribute{
+				Description: "How long to wait for the action to finish.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// validate checks that the invoke timeout, if configured, parses as a
+// duration.
+func (t *invokeTimeoutsModel) validate() diag.Diagnostics {
+	_, diags := t.InvokeTimeout(0)
+	return diags
+}
+
+// InvokeTimeout returns the configured invoke timeout, or def if unset.
+func (t *invokeTimeoutsModel) InvokeTimeout(def time.Duration) (time.Duration, diag.Diagnostics) {
+	if t == nil {
+		return def, nil
+	}
+	return parseTimeout("invoke", t.Invoke, def)
+}
+
+func parseTimeout(name string, v types.String, def time.Duration) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if v.IsNull() || v.IsUnknown() {
+		return def, diags
+	}
+	d, err := time.ParseDuration(v.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeouts").AtName(name), "Invalid timeout",
+			fmt.Sprintf("timeouts.%s must be a duration such as \"10m\": %s", name, err))
+		return def, diags
+	}
+	return d, diags
+}